@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"strings"
 
 	"github.com/Gu1llaum-3/tinymonitor/internal/config"
 	"github.com/spf13/cobra"
@@ -82,11 +83,64 @@ func printGlobalSettings(cfg *config.Config) {
 		fmt.Printf("  Cooldown:  %ds\n", cfg.Cooldown)
 	}
 
+	switch n := len(cfg.Alerts.Escalation); {
+	case n == 1:
+		fmt.Println("  Escalate:  1 escalation policy")
+	case n > 1:
+		fmt.Printf("  Escalate:  %d escalation policies\n", n)
+	}
+
 	if cfg.LogFile == "" {
 		fmt.Println("  Log File:  (stdout)")
 	} else {
 		fmt.Printf("  Log File:  %s\n", cfg.LogFile)
 	}
+
+	fmt.Printf("  State Dir: %s\n", cfg.StateDir)
+
+	if cfg.Recovery.Hysteresis > 0 || cfg.Recovery.Duration > 0 || len(cfg.Recovery.Rules) > 0 {
+		fmt.Printf("  Recovery:  hysteresis: %.0f%%    duration: %ds    %d rule(s)\n",
+			cfg.Recovery.Hysteresis, cfg.Recovery.Duration, len(cfg.Recovery.Rules))
+	} else {
+		fmt.Println("  Recovery:  immediate")
+	}
+
+	if cfg.Flapping.Enabled {
+		fmt.Printf("  Flapping:  > %d changes in %ds    stable after %ds\n",
+			cfg.Flapping.Threshold, cfg.Flapping.Window, cfg.Flapping.StableTime)
+	} else {
+		fmt.Println("  Flapping:  (disabled)")
+	}
+
+	if cfg.Alerts.Grouping.Enabled {
+		fmt.Printf("  Grouping:  wait %ds    interval %ds\n", cfg.Alerts.Grouping.GroupWait, cfg.Alerts.Grouping.GroupInterval)
+	}
+
+	if len(cfg.Trends) > 0 {
+		fmt.Printf("  Trends:    %d trend threshold(s)\n", len(cfg.Trends))
+	}
+
+	if len(cfg.Anomalies) > 0 {
+		fmt.Printf("  Anomaly:   %d adaptive threshold(s)\n", len(cfg.Anomalies))
+	}
+
+	if len(cfg.Rules) > 0 {
+		fmt.Printf("  Rules:     %d composite rule(s)\n", len(cfg.Rules))
+	}
+
+	if len(cfg.Alerts.Inhibit) > 0 {
+		fmt.Printf("  Inhibit:   %d rule(s)\n", len(cfg.Alerts.Inhibit))
+	}
+
+	if len(cfg.Maintenance) > 0 {
+		fmt.Printf("  Windows:   %d maintenance window(s)\n", len(cfg.Maintenance))
+	}
+
+	if cfg.OutboundHeartbeat.Enabled {
+		fmt.Printf("  Heartbeat: %s %s every %ds\n", strings.ToUpper(cfg.OutboundHeartbeat.Method), cfg.OutboundHeartbeat.URL, cfg.OutboundHeartbeat.Interval)
+	} else {
+		fmt.Println("  Heartbeat: (disabled)")
+	}
 }
 
 func printMetrics(cfg *config.Config) {
@@ -117,6 +171,9 @@ func printMetrics(cfg *config.Config) {
 		if len(cfg.Filesystem.Exclude) > 0 {
 			excludeInfo = fmt.Sprintf("    exclude: %d paths", len(cfg.Filesystem.Exclude))
 		}
+		if len(cfg.Filesystem.ExpectedMounts) > 0 {
+			excludeInfo += fmt.Sprintf("    expected: %d mounts", len(cfg.Filesystem.ExpectedMounts))
+		}
 		fmt.Printf("  [✓] Filesystem  warning: %.0f%%    critical: %.0f%%%s%s\n",
 			cfg.Filesystem.Warning, cfg.Filesystem.Critical, dur, excludeInfo)
 	} else {
@@ -159,10 +216,142 @@ func printMetrics(cfg *config.Config) {
 
 	// Reboot
 	if cfg.Reboot.Enabled {
-		fmt.Println("  [✓] Reboot      (checks /var/run/reboot-required)")
+		var checks []string
+		checks = append(checks, "reboot-required flag")
+		if cfg.Reboot.KernelCheck {
+			checks = append(checks, "kernel version")
+		}
+		if cfg.Reboot.NeedsRestarting {
+			checks = append(checks, "needs-restarting")
+		}
+		if cfg.Reboot.DeletedLibs {
+			checks = append(checks, "deleted libraries")
+		}
+		fmt.Printf("  [✓] Reboot      (checks %s)\n", strings.Join(checks, ", "))
 	} else {
 		fmt.Println("  [✗] Reboot      (disabled)")
 	}
+
+	// Kernel events
+	if cfg.Kernel.Enabled {
+		if cfg.Kernel.Kmsg {
+			fmt.Printf("  [✓] Kernel      OOM kills + kmsg (%d patterns)\n", len(cfg.Kernel.Patterns))
+		} else {
+			fmt.Println("  [✓] Kernel      OOM kills")
+		}
+	} else {
+		fmt.Println("  [✗] Kernel      (disabled)")
+	}
+
+	// Software RAID
+	if cfg.RAID.Enabled {
+		fmt.Printf("  [✓] RAID        (checks %s)\n", cfg.RAID.MdstatPath)
+	} else {
+		fmt.Println("  [✗] RAID        (disabled)")
+	}
+
+	// Kernel table limits
+	if cfg.Limits.Enabled {
+		dur := formatDuration(cfg.Limits.Duration)
+		fmt.Printf("  [✓] Limits      warning: %.0f%%    critical: %.0f%%%s\n",
+			cfg.Limits.Warning, cfg.Limits.Critical, dur)
+	} else {
+		fmt.Println("  [✗] Limits      (disabled)")
+	}
+
+	// TCP
+	if cfg.TCP.Enabled {
+		ports := "all ports"
+		if len(cfg.TCP.Ports) > 0 {
+			ports = fmt.Sprintf("%d port(s)", len(cfg.TCP.Ports))
+		}
+		fmt.Printf("  [✓] TCP         close_wait: %.0f/%.0f    time_wait: %.0f/%.0f    (%s)\n",
+			cfg.TCP.CloseWait.Warning, cfg.TCP.CloseWait.Critical,
+			cfg.TCP.TimeWait.Warning, cfg.TCP.TimeWait.Critical, ports)
+	} else {
+		fmt.Println("  [✗] TCP         (disabled)")
+	}
+
+	// Temperature
+	if cfg.Temperature.Enabled {
+		dur := formatDuration(cfg.Temperature.Duration)
+		if cfg.Temperature.Warning > 0 || cfg.Temperature.Critical > 0 {
+			fmt.Printf("  [✓] Temperature warning: %.0f°C   critical: %.0f°C%s\n",
+				cfg.Temperature.Warning, cfg.Temperature.Critical, dur)
+		} else {
+			fmt.Printf("  [✓] Temperature (sensor limits)%s\n", dur)
+		}
+	} else {
+		fmt.Println("  [✗] Temperature (disabled)")
+	}
+
+	// Pending updates
+	if cfg.Updates.Enabled {
+		fmt.Printf("  [✓] Updates     manager: %s    security max age: %dd    interval: %ds\n",
+			cfg.Updates.Manager, cfg.Updates.SecurityMaxAge, cfg.Updates.Interval)
+	} else {
+		fmt.Println("  [✗] Updates     (disabled)")
+	}
+
+	// Uptime
+	if cfg.Uptime.Enabled {
+		maxUptime := "no max uptime"
+		if cfg.Uptime.MaxDays > 0 {
+			maxUptime = fmt.Sprintf("max uptime: %dd", cfg.Uptime.MaxDays)
+		}
+		fmt.Printf("  [✓] Uptime      (reboot detection)    %s\n", maxUptime)
+	} else {
+		fmt.Println("  [✗] Uptime      (disabled)")
+	}
+
+	// Cgroup
+	if cfg.Cgroup.Enabled {
+		target := "own cgroup"
+		if len(cfg.Cgroup.Paths) > 0 {
+			target = fmt.Sprintf("%d cgroup(s)", len(cfg.Cgroup.Paths))
+		}
+		fmt.Printf("  [✓] Cgroup      memory: %.0f%% / %.0f%%    throttled: %.0f%% / %.0f%%    (%s)\n",
+			cfg.Cgroup.Memory.Warning, cfg.Cgroup.Memory.Critical,
+			cfg.Cgroup.Throttle.Warning, cfg.Cgroup.Throttle.Critical, target)
+	} else {
+		fmt.Println("  [✗] Cgroup      (disabled)")
+	}
+
+	// Containers
+	if cfg.Containers.Enabled {
+		filter := "all containers"
+		if len(cfg.Containers.Names) > 0 || len(cfg.Containers.Labels) > 0 {
+			filter = fmt.Sprintf("%d name(s), %d label(s)", len(cfg.Containers.Names), len(cfg.Containers.Labels))
+		}
+		fmt.Printf("  [✓] Containers  socket: %s    (%s)\n", cfg.Containers.Socket, filter)
+	} else {
+		fmt.Println("  [✗] Containers  (disabled)")
+	}
+
+	// Files
+	if cfg.Files.Enabled {
+		fmt.Printf("  [✓] Files       %d check(s)    interval: %ds\n", len(cfg.Files.Checks), cfg.Files.Interval)
+	} else {
+		fmt.Println("  [✗] Files       (disabled)")
+	}
+
+	// NTP
+	if cfg.NTP.Enabled {
+		server := "sync status only"
+		if cfg.NTP.Server != "" {
+			server = fmt.Sprintf("server: %s    warning: %.0fms    critical: %.0fms", cfg.NTP.Server, cfg.NTP.Warning, cfg.NTP.Critical)
+		}
+		fmt.Printf("  [✓] NTP         %s\n", server)
+	} else {
+		fmt.Println("  [✗] NTP         (disabled)")
+	}
+
+	// Heartbeats
+	if cfg.HeartbeatListener.Enabled {
+		fmt.Printf("  [✓] Heartbeats  listen: %s    %d heartbeat(s)\n", cfg.HeartbeatListener.Listen, len(cfg.Heartbeats))
+	} else {
+		fmt.Println("  [✗] Heartbeats  (disabled)")
+	}
 }
 
 func printAlertProviders(cfg *config.Config) {
@@ -178,41 +367,52 @@ func printAlertProviders(cfg *config.Config) {
 
 	// Ntfy
 	if cfg.Alerts.Ntfy.Enabled {
-		fmt.Printf("  [✓] Ntfy        %s\n", cfg.Alerts.Ntfy.TopicURL)
+		fmt.Printf("  [✓] Ntfy        %s%s\n", cfg.Alerts.Ntfy.TopicURL, formatQuietHours(cfg.Alerts.Ntfy.QuietHours))
 	} else {
 		fmt.Println("  [✗] Ntfy")
 	}
 
 	// Google Chat
 	if cfg.Alerts.GoogleChat.Enabled {
-		fmt.Printf("  [✓] Google Chat %s\n", truncateURL(cfg.Alerts.GoogleChat.WebhookURL))
+		fmt.Printf("  [✓] Google Chat %s%s\n", truncateURL(cfg.Alerts.GoogleChat.WebhookURL), formatQuietHours(cfg.Alerts.GoogleChat.QuietHours))
 	} else {
 		fmt.Println("  [✗] Google Chat")
 	}
 
 	// SMTP
 	if cfg.Alerts.SMTP.Enabled {
-		fmt.Printf("  [✓] SMTP        %s:%d → %d recipient(s)\n",
-			cfg.Alerts.SMTP.Host, cfg.Alerts.SMTP.Port, len(cfg.Alerts.SMTP.ToAddrs))
+		fmt.Printf("  [✓] SMTP        %s:%d → %d recipient(s)%s\n",
+			cfg.Alerts.SMTP.Host, cfg.Alerts.SMTP.Port, len(cfg.Alerts.SMTP.ToAddrs), formatQuietHours(cfg.Alerts.SMTP.QuietHours))
 	} else {
 		fmt.Println("  [✗] SMTP")
 	}
 
 	// Webhook
 	if cfg.Alerts.Webhook.Enabled {
-		fmt.Printf("  [✓] Webhook     %s\n", truncateURL(cfg.Alerts.Webhook.URL))
+		fmt.Printf("  [✓] Webhook     %s%s\n", truncateURL(cfg.Alerts.Webhook.URL), formatQuietHours(cfg.Alerts.Webhook.QuietHours))
 	} else {
 		fmt.Println("  [✗] Webhook")
 	}
 
 	// Gotify
 	if cfg.Alerts.Gotify.Enabled {
-		fmt.Printf("  [✓] Gotify      %s\n", cfg.Alerts.Gotify.URL)
+		fmt.Printf("  [✓] Gotify      %s%s\n", cfg.Alerts.Gotify.URL, formatQuietHours(cfg.Alerts.Gotify.QuietHours))
 	} else {
 		fmt.Println("  [✗] Gotify")
 	}
 }
 
+func formatQuietHours(schedules []config.QuietHours) string {
+	if len(schedules) == 0 {
+		return ""
+	}
+	periods := make([]string, 0, len(schedules))
+	for _, q := range schedules {
+		periods = append(periods, q.Start+"-"+q.End)
+	}
+	return "    quiet: " + strings.Join(periods, ", ")
+}
+
 func formatDuration(d int) string {
 	if d > 0 {
 		return fmt.Sprintf("    duration: %ds", d)
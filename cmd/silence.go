@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Gu1llaum-3/tinymonitor/internal/config"
+	"github.com/Gu1llaum-3/tinymonitor/internal/silence"
+	"github.com/spf13/cobra"
+)
+
+var silenceCmd = &cobra.Command{
+	Use:   "silence",
+	Short: "Manage alert silences",
+	Long: `Manage ad-hoc alert silences.
+
+While a silence is active, matching alerts are not delivered. Alert states
+keep being tracked: an alert still firing when the silence ends is delivered
+then, and the recovery of a silenced alert is not sent.
+
+Silences are stored in the state directory and apply to the running service
+immediately.
+
+Available subcommands:
+  add     - Create a silence
+  list    - List active and upcoming silences
+  expire  - End a silence now`,
+}
+
+var silenceAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Create a silence",
+	Long: `Create a silence for components matching a glob ("*" matches any
+characters, "/" included).
+
+Examples:
+  tinymonitor silence add --component 'DISK:*' --for 2h --reason "disk migration"
+  tinymonitor silence add --component CPU --level WARNING --for 30m
+  tinymonitor silence add --component '*' --for 1h --reason "kernel upgrade"`,
+	Run: runSilenceAdd,
+}
+
+var silenceListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List active and upcoming silences",
+	Run:   runSilenceList,
+}
+
+var silenceExpireCmd = &cobra.Command{
+	Use:   "expire <id>",
+	Short: "End a silence now",
+	Args:  cobra.ExactArgs(1),
+	Run:   runSilenceExpire,
+}
+
+var (
+	silenceComponent string
+	silenceFor       time.Duration
+	silenceStart     string
+	silenceReason    string
+	silenceLevels    []string
+)
+
+func init() {
+	rootCmd.AddCommand(silenceCmd)
+	silenceCmd.AddCommand(silenceAddCmd)
+	silenceCmd.AddCommand(silenceListCmd)
+	silenceCmd.AddCommand(silenceExpireCmd)
+
+	silenceAddCmd.Flags().StringVar(&silenceComponent, "component", "", "Component glob, e.g. 'DISK:*' (required)")
+	silenceAddCmd.Flags().DurationVar(&silenceFor, "for", time.Hour, "Duration of the silence, e.g. 30m, 2h")
+	silenceAddCmd.Flags().StringVar(&silenceStart, "start", "", "Start time (RFC 3339 or \"2006-01-02 15:04\", default now)")
+	silenceAddCmd.Flags().StringVar(&silenceReason, "reason", "", "Reason, shown in logs and listings")
+	silenceAddCmd.Flags().StringSliceVar(&silenceLevels, "level", nil, "Only silence these levels (WARNING, CRITICAL)")
+	silenceAddCmd.MarkFlagRequired("component")
+}
+
+// silenceStore opens the silences of the configured state directory
+func silenceStore() *silence.Store {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
+		os.Exit(1)
+	}
+	return silence.NewStore(cfg.StateDir)
+}
+
+func runSilenceAdd(cmd *cobra.Command, args []string) {
+	if silenceFor <= 0 {
+		fmt.Fprintln(os.Stderr, "Error: --for must be positive")
+		os.Exit(1)
+	}
+
+	start := time.Now()
+	if silenceStart != "" {
+		var err error
+		start, err = parseSilenceTime(silenceStart)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	for i, level := range silenceLevels {
+		silenceLevels[i] = strings.ToUpper(level)
+		if silenceLevels[i] != "WARNING" && silenceLevels[i] != "CRITICAL" {
+			fmt.Fprintf(os.Stderr, "Error: --level must be WARNING or CRITICAL (got %q)\n", level)
+			os.Exit(1)
+		}
+	}
+
+	created, err := silenceStore().Add(silence.Silence{
+		Component: silenceComponent,
+		Levels:    silenceLevels,
+		Reason:    silenceReason,
+		CreatedBy: currentUser(),
+		StartsAt:  start,
+		EndsAt:    start.Add(silenceFor),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating silence: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Silence %s created: %s until %s\n", created.ID, created.Component, created.EndsAt.Format("2006-01-02 15:04"))
+}
+
+func runSilenceList(cmd *cobra.Command, args []string) {
+	silences, err := silenceStore().List()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading silences: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(silences) == 0 {
+		fmt.Println("No active silences.")
+		return
+	}
+
+	now := time.Now()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tCOMPONENT\tLEVELS\tSTATE\tENDS\tCREATED BY\tREASON")
+	for _, s := range silences {
+		levels := "all"
+		if len(s.Levels) > 0 {
+			levels = strings.Join(s.Levels, ",")
+		}
+		status := "active"
+		if !s.Active(now) {
+			status = "starts " + s.StartsAt.Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			s.ID, s.Component, levels, status, s.EndsAt.Format("2006-01-02 15:04"), s.CreatedBy, s.Reason)
+	}
+	w.Flush()
+}
+
+func runSilenceExpire(cmd *cobra.Command, args []string) {
+	if err := silenceStore().Expire(args[0]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Silence %s expired\n", args[0])
+}
+
+func parseSilenceTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02 15:04", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid start time %q", value)
+	}
+	return t, nil
+}
+
+// currentUser returns the user behind sudo if any
+func currentUser() string {
+	if name := os.Getenv("SUDO_USER"); name != "" {
+		return name
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/Gu1llaum-3/tinymonitor/internal/config"
@@ -27,6 +28,8 @@ func NewGoogleChatProvider(cfg config.GoogleChatConfig) *GoogleChatProvider {
 			Enabled:      cfg.Enabled,
 			Levels:       cfg.Levels,
 			Rules:        cfg.Rules,
+			Individual:   cfg.IndividualAlerts,
+			quiet:        newQuietHours("google_chat", cfg.QuietHours),
 		},
 		webhookURL: cfg.WebhookURL,
 	}
@@ -53,12 +56,26 @@ func (p *GoogleChatProvider) Send(alert models.Alert) error {
 		icon = "✅"
 		fontColor = "#00AA00"
 		titleText = "RECOVERED : " + alert.Component
+	case models.SeverityFlapping:
+		icon = "🔁"
+		fontColor = "#8E44AD"
+		titleText = "FLAPPING : " + alert.Component
+	case models.SeverityStable:
+		icon = "☑️"
+		fontColor = "#1E90FF"
+		titleText = "STABLE : " + alert.Component
 	default:
 		icon = "ℹ️"
 		fontColor = "#000000"
 		titleText = "INFO : " + alert.Component
 	}
 
+	// Grouped alerts list one component per line
+	value := alert.Value
+	if alert.IsGroup() {
+		value = strings.ReplaceAll(value, "\n", "<br>")
+	}
+
 	// System Info
 	hostname := utils.GetHostname()
 	executionTime := time.Now().Format("2006-01-02 15:04:05")
@@ -96,7 +113,7 @@ func (p *GoogleChatProvider) Send(alert models.Alert) error {
 								{
 									"decoratedText": map[string]interface{}{
 										"topLabel":  "Current Value",
-										"text":      fmt.Sprintf("<font color=\"%s\"><b>%s</b></font>", fontColor, alert.Value),
+										"text":      fmt.Sprintf("<font color=\"%s\"><b>%s</b></font>", fontColor, value),
 										"startIcon": map[string]string{"knownIcon": "DESCRIPTION"},
 									},
 								},
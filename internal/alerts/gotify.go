@@ -28,6 +28,8 @@ func NewGotifyProvider(cfg config.GotifyConfig) *GotifyProvider {
 			Enabled:      cfg.Enabled,
 			Levels:       cfg.Levels,
 			Rules:        cfg.Rules,
+			Individual:   cfg.IndividualAlerts,
+			quiet:        newQuietHours("gotify", cfg.QuietHours),
 		},
 		url:   cfg.URL,
 		token: cfg.Token,
@@ -58,6 +60,10 @@ func (p *GotifyProvider) Send(alert models.Alert) error {
 		priority = 5
 	case models.SeverityRecovery:
 		priority = 3
+	case models.SeverityFlapping:
+		priority = 5
+	case models.SeverityStable:
+		priority = 3
 	default:
 		priority = 2
 	}
@@ -70,6 +76,12 @@ func (p *GotifyProvider) Send(alert models.Alert) error {
 	loadAvg := utils.GetLoadAvg()
 	uptimePretty := utils.GetUptime()
 
+	// Grouped alerts list one component per line, below the label
+	value := alert.Value
+	if alert.IsGroup() {
+		value = "\n" + value
+	}
+
 	// Enriched message (Markdown supported)
 	fullMessage := fmt.Sprintf(`**Component** : %s
 **Value**     : %s
@@ -82,7 +94,7 @@ __Machine Context__
 ⚙️ **Load Avg**  : `+"`%s`"+`
 ⏱️ **Uptime**    : `+"`%s`"+`
 🕒 **Time**      : %s`,
-		alert.Component, alert.Value, alert.Level,
+		alert.Component, value, alert.Level,
 		hostname, ipPrivate, ipPublic, loadAvg, uptimePretty, executionTime)
 
 	payload := map[string]interface{}{
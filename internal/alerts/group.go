@@ -0,0 +1,110 @@
+package alerts
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/Gu1llaum-3/tinymonitor/internal/models"
+)
+
+// alertGroup is the pending batch of a provider
+type alertGroup struct {
+	provider  Provider
+	alerts    []models.Alert
+	timer     *time.Timer
+	lastFlush time.Time
+}
+
+// enqueue sends an alert to a provider, through its group when grouping is
+// enabled and the provider accepts it. The first alert of a batch waits
+// group_wait seconds for others; a batch following a previous one is sent no
+// earlier than group_interval seconds after it.
+func (m *Manager) enqueue(provider Provider, alert models.Alert) {
+	if !m.grouping.Enabled || !provider.Grouped() {
+		m.queue(provider, alert)
+		return
+	}
+
+	m.groupMu.Lock()
+	defer m.groupMu.Unlock()
+
+	if m.closed {
+		return
+	}
+
+	g := m.groups[provider.Name()]
+	if g == nil {
+		g = &alertGroup{provider: provider}
+		m.groups[provider.Name()] = g
+	}
+
+	// The latest state of a component replaces the earlier one
+	replaced := false
+	for i, pending := range g.alerts {
+		if pending.Component == alert.Component {
+			g.alerts[i] = alert
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		g.alerts = append(g.alerts, alert)
+	}
+
+	if g.timer == nil {
+		wait := time.Duration(m.grouping.GroupWait) * time.Second
+		if !g.lastFlush.IsZero() {
+			next := time.Until(g.lastFlush.Add(time.Duration(m.grouping.GroupInterval) * time.Second))
+			if next > wait {
+				wait = next
+			}
+		}
+		slog.Debug("Alert grouped", "provider", provider.Name(), "component", alert.Component, "wait", wait)
+		g.timer = time.AfterFunc(wait, func() { m.flushGroup(provider.Name()) })
+	}
+}
+
+// flushGroup sends the pending batch of a provider: a single alert as is,
+// several as one grouped notification
+func (m *Manager) flushGroup(name string) {
+	m.groupMu.Lock()
+	defer m.groupMu.Unlock()
+
+	if m.closed {
+		return
+	}
+	m.sendGroup(m.groups[name])
+}
+
+// flushGroups sends every pending batch and stops grouping, on shutdown
+func (m *Manager) flushGroups() {
+	m.groupMu.Lock()
+	defer m.groupMu.Unlock()
+
+	for _, g := range m.groups {
+		if g.timer != nil {
+			g.timer.Stop()
+		}
+		m.sendGroup(g)
+	}
+	m.closed = true
+}
+
+func (m *Manager) sendGroup(g *alertGroup) {
+	if g == nil || len(g.alerts) == 0 {
+		return
+	}
+
+	alerts := g.alerts
+	g.alerts = nil
+	g.timer = nil
+	g.lastFlush = time.Now()
+
+	if len(alerts) == 1 {
+		m.queue(g.provider, alerts[0])
+		return
+	}
+
+	slog.Info("Sending grouped alerts", "provider", g.provider.Name(), "count", len(alerts))
+	m.queue(g.provider, models.NewGroupAlert(alerts))
+}
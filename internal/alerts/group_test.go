@@ -0,0 +1,71 @@
+package alerts
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/Gu1llaum-3/tinymonitor/internal/config"
+	"github.com/Gu1llaum-3/tinymonitor/internal/models"
+)
+
+type recordingProvider struct {
+	BaseProvider
+	mu   sync.Mutex
+	sent []models.Alert
+}
+
+func (p *recordingProvider) Send(alert models.Alert) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sent = append(p.sent, alert)
+	return nil
+}
+
+func newTestManager(grouping config.GroupingConfig, providers ...Provider) *Manager {
+	m := NewManager(config.AlertsConfig{Grouping: grouping})
+	m.providers = providers
+	return m
+}
+
+func TestGrouping(t *testing.T) {
+	grouping := config.GroupingConfig{Enabled: true, GroupWait: 60, GroupInterval: 60}
+	grouped := &recordingProvider{BaseProvider: BaseProvider{ProviderName: "grouped", Enabled: true}}
+	individual := &recordingProvider{BaseProvider: BaseProvider{ProviderName: "individual", Enabled: true, Individual: true}}
+
+	m := newTestManager(grouping, grouped, individual)
+	m.SendAlert("cpu", models.SeverityWarning, "85%")
+	m.SendAlert("ping", models.SeverityCritical, "10.0.0.1 unreachable")
+	m.SendAlert("cpu", models.SeverityCritical, "97%")
+	m.Shutdown() // Sends the pending batch
+
+	if len(grouped.sent) != 1 {
+		t.Fatalf("grouped provider received %d notifications, want 1", len(grouped.sent))
+	}
+	alert := grouped.sent[0]
+	if !alert.IsGroup() || len(alert.Group) != 2 {
+		t.Fatalf("expected a group of 2 alerts, got %+v", alert)
+	}
+	if alert.Level != models.SeverityCritical {
+		t.Errorf("group level = %s, want CRITICAL", alert.Level)
+	}
+	if alert.Component != "cpu, ping" {
+		t.Errorf("group component = %q, want %q", alert.Component, "cpu, ping")
+	}
+	if alert.Group[0].Value != "97%" {
+		t.Errorf("cpu value = %q, want the latest %q", alert.Group[0].Value, "97%")
+	}
+
+	if len(individual.sent) != 3 {
+		t.Errorf("individual provider received %d notifications, want 3", len(individual.sent))
+	}
+
+	// A single alert is sent as is
+	single := &recordingProvider{BaseProvider: BaseProvider{ProviderName: "single", Enabled: true}}
+	m = newTestManager(grouping, single)
+	m.SendAlert("memory", models.SeverityWarning, "75%")
+	m.Shutdown()
+
+	if len(single.sent) != 1 || single.sent[0].IsGroup() || single.sent[0].Component != "memory" {
+		t.Errorf("expected the memory alert alone, got %+v", single.sent)
+	}
+}
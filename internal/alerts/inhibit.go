@@ -0,0 +1,47 @@
+package alerts
+
+import (
+	"strings"
+
+	"github.com/Gu1llaum-3/tinymonitor/internal/config"
+	"github.com/Gu1llaum-3/tinymonitor/internal/models"
+	"github.com/Gu1llaum-3/tinymonitor/internal/silence"
+)
+
+// inhibitedBy returns the firing alert inhibiting a component, if any: an
+// alert matching the source of a rule whose targets match the component.
+// The caller holds m.mu.
+func (m *Manager) inhibitedBy(component string) (string, bool) {
+	for _, rule := range m.inhibitRules {
+		if !matchAny(rule.Targets, component) {
+			continue
+		}
+		for source, level := range m.firing {
+			if source != component && silence.MatchGlob(rule.Source, source) && matchSourceLevel(rule, level) {
+				return source, true
+			}
+		}
+	}
+	return "", false
+}
+
+func matchAny(patterns []string, component string) bool {
+	for _, pattern := range patterns {
+		if silence.MatchGlob(pattern, component) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchSourceLevel(rule config.InhibitRule, level models.Severity) bool {
+	if len(rule.SourceLevels) == 0 {
+		return true
+	}
+	for _, l := range rule.SourceLevels {
+		if strings.EqualFold(l, string(level)) {
+			return true
+		}
+	}
+	return false
+}
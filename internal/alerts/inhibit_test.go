@@ -0,0 +1,56 @@
+package alerts
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Gu1llaum-3/tinymonitor/internal/config"
+	"github.com/Gu1llaum-3/tinymonitor/internal/models"
+)
+
+func TestInhibition(t *testing.T) {
+	provider := &recordingProvider{BaseProvider: BaseProvider{ProviderName: "test", Enabled: true}}
+	m := NewManager(config.AlertsConfig{
+		Inhibit: []config.InhibitRule{
+			{Source: "DISK:/", SourceLevels: []string{"CRITICAL"}, Targets: []string{"I/O", "LOAD*"}},
+		},
+	})
+	m.providers = []Provider{provider}
+
+	// A WARNING source does not inhibit
+	m.SendAlert("DISK:/", models.SeverityWarning, "85%")
+	m.SendAlert("I/O", models.SeverityWarning, "120 MB/s")
+
+	m.SendAlert("DISK:/", models.SeverityCritical, "97%")
+	m.SendAlert("LOAD5", models.SeverityWarning, "4.2")
+	m.SendAlert("LOAD15", models.SeverityWarning, "3.1")
+	m.SendAlert("MEMORY", models.SeverityWarning, "75%")
+
+	// LOAD15 recovers while inhibited: nothing to send
+	m.SendRecovery("LOAD15", models.SeverityWarning, "1.0")
+	m.ReleaseHeld()
+
+	// The source resolves while LOAD5 still fires: it is delivered
+	m.SendRecovery("DISK:/", models.SeverityCritical, "60%")
+	m.ReleaseHeld()
+	m.Shutdown()
+
+	var got []string
+	for _, alert := range provider.sent {
+		got = append(got, string(alert.Level)+" "+alert.Component)
+	}
+	want := []string{
+		"WARNING DISK:/",
+		"WARNING I/O",
+		"CRITICAL DISK:/",
+		"WARNING MEMORY",
+		"RECOVERED DISK:/",
+		"WARNING LOAD5",
+	}
+	// Workers send concurrently
+	slices.Sort(got)
+	slices.Sort(want)
+	if !slices.Equal(got, want) {
+		t.Errorf("sent %v, want %v", got, want)
+	}
+}
@@ -3,12 +3,19 @@ package alerts
 import (
 	"context"
 	"log/slog"
+	"slices"
 	"sync"
 
 	"github.com/Gu1llaum-3/tinymonitor/internal/config"
 	"github.com/Gu1llaum-3/tinymonitor/internal/models"
 )
 
+// Silencer decides whether an alert must not be delivered (maintenance
+// windows, silences) and returns the reason
+type Silencer interface {
+	Silenced(component string, level models.Severity) (string, bool)
+}
+
 // Manager distributes alerts to configured providers
 type Manager struct {
 	providers []Provider
@@ -16,6 +23,37 @@ type Manager struct {
 	wg        sync.WaitGroup
 	ctx       context.Context
 	cancel    context.CancelFunc
+
+	// Silenced alerts are held instead of delivered: they are delivered
+	// when the silence ends if still firing, or dropped with their recovery.
+	// notified is the last level delivered per component, so recoveries are
+	// only sent for alerts someone was told about, and reached the providers
+	// it was delivered to, so they are sent to those providers only.
+	silencer Silencer
+	mu       sync.Mutex
+	held     map[string]heldAlert
+	notified map[string]models.Severity
+	reached  map[string]map[string]bool
+
+	// Inhibited alerts are held the same way until no firing alert inhibits
+	// them, see inhibit.go. firing is the current level of every alert
+	// raised, delivered or not.
+	inhibitRules []config.InhibitRule
+	inhibited    map[string]heldAlert
+	firing       map[string]models.Severity
+
+	// Alert grouping, see group.go
+	grouping config.GroupingConfig
+	groupMu  sync.Mutex
+	groups   map[string]*alertGroup
+	closed   bool
+}
+
+// heldAlert is a silenced or inhibited alert with the providers it targets
+// (nil = all)
+type heldAlert struct {
+	alert     models.Alert
+	providers []string
 }
 
 type alertTask struct {
@@ -32,6 +70,16 @@ func NewManager(cfg config.AlertsConfig) *Manager {
 		alertChan: make(chan alertTask, 100),
 		ctx:       ctx,
 		cancel:    cancel,
+		held:      make(map[string]heldAlert),
+		notified:  make(map[string]models.Severity),
+		reached:   make(map[string]map[string]bool),
+
+		inhibitRules: cfg.Inhibit,
+		inhibited:    make(map[string]heldAlert),
+		firing:       make(map[string]models.Severity),
+
+		grouping: cfg.Grouping,
+		groups:   make(map[string]*alertGroup),
 	}
 
 	m.loadProviders(cfg)
@@ -99,54 +147,282 @@ func (m *Manager) worker() {
 	}
 }
 
-// SendAlert distributes an alert to all configured and eligible providers
+// SetSilencer sets the silences applied before delivery
+func (m *Manager) SetSilencer(s Silencer) {
+	m.silencer = s
+}
+
+// SendAlert distributes an alert to all configured and eligible providers,
+// unless it is silenced or inhibited
 func (m *Manager) SendAlert(component string, level models.Severity, value string) {
-	alert := models.NewAlert(component, level, value)
+	m.SendAlertTo(component, level, value, nil)
+}
+
+// SendAlertTo distributes an alert to the named eligible providers (all of
+// them when providers is nil), unless it is silenced or inhibited. Escalation
+// steps use it to reach more providers as an incident lasts.
+func (m *Manager) SendAlertTo(component string, level models.Severity, value string, providers []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.firing[component] = level
+	m.route(heldAlert{models.NewAlert(component, level, value), providers})
+}
+
+// route delivers an alert, or holds it while it is silenced or inhibited
+func (m *Manager) route(alert heldAlert) {
+	component, level := alert.alert.Component, alert.alert.Level
+
+	if m.silencer != nil {
+		if reason, ok := m.silencer.Silenced(component, level); ok {
+			slog.Info("Alert silenced",
+				"component", component,
+				"level", level,
+				"reason", reason)
+			m.held[component] = mergeHeld(m.held, alert)
+			delete(m.inhibited, component)
+			return
+		}
+	}
+
+	if source, ok := m.inhibitedBy(component); ok {
+		slog.Info("Alert inhibited",
+			"component", component,
+			"level", level,
+			"source", source)
+		m.inhibited[component] = mergeHeld(m.inhibited, alert)
+		delete(m.held, component)
+		return
+	}
+
+	delete(m.held, component)
+	delete(m.inhibited, component)
+	m.notified[component] = level
+	m.deliver(alert.alert, alert.providers)
+}
+
+// mergeHeld returns the alert to hold, keeping the providers targeted by the
+// alert already held for the component
+func mergeHeld(held map[string]heldAlert, alert heldAlert) heldAlert {
+	previous, ok := held[alert.alert.Component]
+	if !ok {
+		return alert
+	}
+	if previous.providers == nil || alert.providers == nil {
+		alert.providers = nil
+	} else {
+		alert.providers = append(slices.Clone(previous.providers), alert.providers...)
+	}
+	return alert
+}
+
+// deliver queues an alert for the eligible providers among the named ones
+// (all of them when providers is nil)
+func (m *Manager) deliver(alert models.Alert, providers []string) {
+	component, level := alert.Component, alert.Level
 
 	for _, provider := range m.providers {
+		if providers != nil && !slices.Contains(providers, provider.Name()) {
+			continue
+		}
 		if provider.ShouldSend(component, level) {
 			slog.Info("Triggering alert",
 				"provider", provider.Name(),
 				"component", component,
 				"level", level)
 
-			select {
-			case m.alertChan <- alertTask{provider: provider, alert: alert}:
-			default:
-				slog.Warn("Alert channel full, dropping alert",
-					"provider", provider.Name(),
-					"component", component)
-			}
+			m.reach(component, provider)
+			m.enqueue(provider, alert)
+		} else if provider.Defer(alert) {
+			slog.Info("Alert deferred to digest (quiet hours)",
+				"provider", provider.Name(),
+				"component", component,
+				"level", level)
+			m.reach(component, provider)
 		}
 	}
 }
 
-// SendRecovery distributes a recovery notification to all configured providers
+// reach records that a provider was told about an alert of a component
+func (m *Manager) reach(component string, provider Provider) {
+	if m.reached[component] == nil {
+		m.reached[component] = make(map[string]bool)
+	}
+	m.reached[component][provider.Name()] = true
+}
+
+// SendRecovery distributes a recovery notification to all configured providers.
+// Recoveries of alerts that were never delivered (silenced, inhibited) are
+// dropped; recoveries of delivered alerts are sent even during a silence.
 func (m *Manager) SendRecovery(component string, previousLevel models.Severity, value string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, held := m.held[component]
+	_, inhibited := m.inhibited[component]
+	if held || inhibited {
+		notified, ok := m.notified[component]
+		if !ok {
+			m.resolve(component)
+			slog.Info("Undelivered alert recovered, recovery not sent", "component", component)
+			return
+		}
+		previousLevel = notified
+	}
+	reached := m.reached[component]
+	m.resolve(component)
+
 	alert := models.NewRecoveryAlert(component, previousLevel, value)
 
 	for _, provider := range m.providers {
-		// Send recovery to providers that would have received the original alert
+		// Send recovery to providers that received the original alert
+		if reached != nil && !reached[provider.Name()] {
+			continue
+		}
 		if provider.ShouldSend(component, previousLevel) {
 			slog.Info("Triggering recovery",
 				"provider", provider.Name(),
 				"component", component,
 				"previous_level", previousLevel)
 
-			select {
-			case m.alertChan <- alertTask{provider: provider, alert: alert}:
-			default:
-				slog.Warn("Alert channel full, dropping recovery",
-					"provider", provider.Name(),
-					"component", component)
-			}
+			m.enqueue(provider, alert)
+		} else if provider.Defer(alert) {
+			slog.Info("Recovery deferred to digest (quiet hours)",
+				"provider", provider.Name(),
+				"component", component)
 		}
 	}
 }
 
-// Shutdown gracefully shuts down the manager
+// Resolve forgets an alert that recovered without a recovery notification
+// (send_recovery = false)
+func (m *Manager) Resolve(component string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.resolve(component)
+}
+
+func (m *Manager) resolve(component string) {
+	delete(m.firing, component)
+	delete(m.held, component)
+	delete(m.inhibited, component)
+	delete(m.notified, component)
+	delete(m.reached, component)
+}
+
+// SendFlapping notifies that a component started flapping. It is routed like
+// the worst level seen, as recoveries are.
+func (m *Manager) SendFlapping(component string, worstLevel models.Severity, value string) {
+	m.dispatchFlapping(models.NewFlappingAlert(component, worstLevel, value))
+}
+
+// SendStable sends the summary of a component that stopped flapping
+func (m *Manager) SendStable(component string, worstLevel models.Severity, value string) {
+	m.dispatchFlapping(models.NewStableAlert(component, worstLevel, value))
+}
+
+func (m *Manager) dispatchFlapping(alert models.Alert) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.silencer != nil {
+		if reason, ok := m.silencer.Silenced(alert.Component, alert.PreviousLevel); ok {
+			slog.Info("Flapping notification silenced",
+				"component", alert.Component,
+				"level", alert.Level,
+				"reason", reason)
+			return
+		}
+	}
+
+	if source, ok := m.inhibitedBy(alert.Component); ok {
+		slog.Info("Flapping notification inhibited",
+			"component", alert.Component,
+			"level", alert.Level,
+			"source", source)
+		return
+	}
+
+	for _, provider := range m.providers {
+		if provider.ShouldSend(alert.Component, alert.PreviousLevel) {
+			slog.Info("Triggering flapping notification",
+				"provider", provider.Name(),
+				"component", alert.Component,
+				"level", alert.Level)
+
+			m.enqueue(provider, alert)
+		} else if provider.Defer(alert) {
+			slog.Info("Flapping notification deferred to digest (quiet hours)",
+				"provider", provider.Name(),
+				"component", alert.Component)
+		}
+	}
+}
+
+// ReleaseHeld delivers the silenced alerts whose silence has ended, the
+// inhibited alerts no firing alert inhibits anymore and the digests of the
+// providers whose quiet hours ended. It is called after each check cycle.
+func (m *Manager) ReleaseHeld() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for component, held := range m.held {
+		if _, ok := m.silencer.Silenced(component, held.alert.Level); ok {
+			continue
+		}
+		slog.Info("Silence ended, delivering held alert",
+			"component", component,
+			"level", held.alert.Level)
+		delete(m.held, component)
+		m.route(held)
+	}
+
+	for component, inhibited := range m.inhibited {
+		if _, ok := m.inhibitedBy(component); ok {
+			continue
+		}
+		slog.Info("Inhibiting alert resolved, delivering inhibited alert",
+			"component", component,
+			"level", inhibited.alert.Level)
+		delete(m.inhibited, component)
+		m.route(inhibited)
+	}
+
+	m.sendDigests(false)
+}
+
+// sendDigests sends the digests of deferred alerts that are due, or all of
+// them when forced
+func (m *Manager) sendDigests(force bool) {
+	for _, provider := range m.providers {
+		if digest, ok := provider.Digest(force); ok {
+			slog.Info("Sending quiet hours digest",
+				"provider", provider.Name(),
+				"count", len(digest.Group))
+			m.queue(provider, digest)
+		}
+	}
+}
+
+// queue hands an alert to the workers
+func (m *Manager) queue(provider Provider, alert models.Alert) {
+	select {
+	case m.alertChan <- alertTask{provider: provider, alert: alert}:
+	default:
+		slog.Warn("Alert channel full, dropping alert",
+			"provider", provider.Name(),
+			"component", alert.Component,
+			"level", alert.Level)
+	}
+}
+
+// Shutdown gracefully shuts down the manager. Pending digests and groups are
+// sent first.
 func (m *Manager) Shutdown() {
-	m.cancel()
+	m.sendDigests(true)
+	m.flushGroups()
 	close(m.alertChan)
 	m.wg.Wait()
+	m.cancel()
 }
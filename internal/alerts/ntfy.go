@@ -26,6 +26,8 @@ func NewNtfyProvider(cfg config.NtfyConfig) *NtfyProvider {
 			Enabled:      cfg.Enabled,
 			Levels:       cfg.Levels,
 			Rules:        cfg.Rules,
+			Individual:   cfg.IndividualAlerts,
+			quiet:        newQuietHours("ntfy", cfg.QuietHours),
 		},
 		topicURL: cfg.TopicURL,
 		token:    cfg.Token,
@@ -51,6 +53,12 @@ func (p *NtfyProvider) Send(alert models.Alert) error {
 	case models.SeverityRecovery:
 		priority = "2"
 		tags = "white_check_mark,recovered"
+	case models.SeverityFlapping:
+		priority = "3"
+		tags = "repeat,flapping"
+	case models.SeverityStable:
+		priority = "2"
+		tags = "ballot_box_with_check,stable"
 	default:
 		priority = "1"
 		tags = "information_source"
@@ -64,6 +72,12 @@ func (p *NtfyProvider) Send(alert models.Alert) error {
 	loadAvg := utils.GetLoadAvg()
 	uptimePretty := utils.GetUptime()
 
+	// Grouped alerts list one component per line, below the label
+	value := alert.Value
+	if alert.IsGroup() {
+		value = "\n" + value
+	}
+
 	// Enriched message (Markdown supported)
 	fullMessage := fmt.Sprintf(`**Component** : %s
 **Value**     : %s
@@ -76,7 +90,7 @@ __Machine Context__
 ⚙️ **Load Avg**  : `+"`%s`"+`
 ⏱️ **Uptime**    : `+"`%s`"+`
 🕒 **Time**      : %s`,
-		alert.Component, alert.Value, alert.Level,
+		alert.Component, value, alert.Level,
 		hostname, ipPrivate, ipPublic, loadAvg, uptimePretty, executionTime)
 
 	req, err := http.NewRequest("POST", p.topicURL, bytes.NewReader([]byte(fullMessage)))
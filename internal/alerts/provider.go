@@ -3,6 +3,8 @@ package alerts
 import (
 	"log/slog"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/Gu1llaum-3/tinymonitor/internal/models"
 )
@@ -17,6 +19,16 @@ type Provider interface {
 
 	// ShouldSend checks if this provider should send an alert for the given component and level
 	ShouldSend(component string, level models.Severity) bool
+
+	// Grouped reports whether alerts may be combined into grouped notifications
+	Grouped() bool
+
+	// Defer keeps an alert ShouldSend refused during quiet hours for the next
+	// digest, and reports whether it did
+	Defer(alert models.Alert) bool
+
+	// Digest returns the deferred alerts once the quiet hours are over
+	Digest(force bool) (models.Alert, bool)
 }
 
 // BaseProvider provides common functionality for alert providers
@@ -25,6 +37,13 @@ type BaseProvider struct {
 	Enabled      bool
 	Levels       []string
 	Rules        map[string][]string
+	Individual   bool // Opt out of alert grouping
+
+	// Quiet hours, see quiet.go
+	quiet    []quietHours
+	now      func() time.Time // Replaceable in tests
+	mu       sync.Mutex
+	deferred []models.Alert
 }
 
 // Name returns the provider name
@@ -32,8 +51,19 @@ func (p *BaseProvider) Name() string {
 	return p.ProviderName
 }
 
-// ShouldSend checks if this provider should send an alert
+// Grouped reports whether alerts may be combined into grouped notifications
+func (p *BaseProvider) Grouped() bool {
+	return !p.Individual
+}
+
+// ShouldSend checks if this provider should send an alert: its rules accept
+// it and no quiet hours apply
 func (p *BaseProvider) ShouldSend(component string, level models.Severity) bool {
+	return p.routes(component, level) && p.quietNow(component, level) == nil
+}
+
+// routes checks if the rules of this provider accept an alert
+func (p *BaseProvider) routes(component string, level models.Severity) bool {
 	// 1. Global check
 	if !p.Enabled {
 		return false
@@ -50,7 +80,7 @@ func (p *BaseProvider) ShouldSend(component string, level models.Severity) bool
 	}
 
 	// 3. Component logic
-	configKey := normalizeComponentName(component)
+	configKey := NormalizeComponentName(component)
 
 	// Look for specific rule, else default, else refuse for safety
 	allowedLevels, ok := p.Rules[configKey]
@@ -61,12 +91,54 @@ func (p *BaseProvider) ShouldSend(component string, level models.Severity) bool
 	return contains(allowedLevels, string(level))
 }
 
-// normalizeComponentName converts the technical component name to a configuration key.
+// NormalizeComponentName converts the technical component name to a configuration key.
 // Load windows ("LOAD5"/"LOAD15") map to "load5"/"load15" via the lowercase default.
-func normalizeComponentName(component string) string {
-	if strings.HasPrefix(component, "DISK:") {
+func NormalizeComponentName(component string) string {
+	if strings.HasPrefix(component, "DISK:") || strings.HasPrefix(component, "MOUNT:") {
 		return "filesystem"
 	}
+	if strings.HasPrefix(component, "RAID:") {
+		return "raid"
+	}
+	if strings.HasPrefix(component, "TCP:") {
+		return "tcp"
+	}
+	if strings.HasPrefix(component, "TEMP:") {
+		return "temperature"
+	}
+	if strings.HasPrefix(component, "CGROUP_") {
+		return "cgroup"
+	}
+	if strings.HasPrefix(component, "CONTAINER") {
+		return "containers"
+	}
+	if strings.HasPrefix(component, "FILES:") {
+		return "files"
+	}
+	if strings.HasPrefix(component, "NTP_") {
+		return "ntp"
+	}
+	if strings.HasPrefix(component, "ANOMALY:") {
+		return "anomaly"
+	}
+	if strings.HasPrefix(component, "TREND:") {
+		return "trend"
+	}
+	if strings.HasPrefix(component, "RULE:") {
+		return "rule"
+	}
+	if strings.HasPrefix(component, "HEARTBEAT") {
+		return "heartbeat"
+	}
+	if component == "RESTART" {
+		return "reboot"
+	}
+	if component == "OOM" {
+		return "kernel"
+	}
+	if component == "BOOT" {
+		return "uptime"
+	}
 	return strings.ToLower(component)
 }
 
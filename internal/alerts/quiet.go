@@ -0,0 +1,118 @@
+package alerts
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Gu1llaum-3/tinymonitor/internal/config"
+	"github.com/Gu1llaum-3/tinymonitor/internal/models"
+	"github.com/Gu1llaum-3/tinymonitor/internal/silence"
+)
+
+// quietHours is a compiled quiet hours schedule of a provider
+type quietHours struct {
+	window *silence.Window
+	rules  []string
+	digest bool
+}
+
+// newQuietHours compiles the quiet hours of a provider. Invalid schedules are
+// logged and ignored (the configuration validation reports them).
+func newQuietHours(provider string, schedules []config.QuietHours) []quietHours {
+	var compiled []quietHours
+	for _, cfg := range schedules {
+		window, err := silence.NewWindow(config.MaintenanceWindow{
+			Name:     provider + " quiet hours",
+			Days:     cfg.Days,
+			Start:    cfg.Start,
+			End:      cfg.End,
+			TimeZone: cfg.TimeZone,
+			Levels:   cfg.Levels,
+		})
+		if err != nil {
+			slog.Warn("Ignoring invalid quiet hours", "provider", provider, "error", err)
+			continue
+		}
+		compiled = append(compiled, quietHours{window: window, rules: cfg.Rules, digest: cfg.Digest})
+	}
+	return compiled
+}
+
+// quietNow returns the quiet hours applying to an alert now, or nil
+func (p *BaseProvider) quietNow(component string, level models.Severity) *quietHours {
+	if len(p.quiet) == 0 {
+		return nil
+	}
+	now := time.Now()
+	if p.now != nil {
+		now = p.now()
+	}
+	key := NormalizeComponentName(component)
+	for i := range p.quiet {
+		q := &p.quiet[i]
+		if len(q.rules) > 0 && !contains(q.rules, key) {
+			continue
+		}
+		if q.window.Matches(component, level, now) {
+			return q
+		}
+	}
+	return nil
+}
+
+// Defer keeps an alert suppressed by quiet hours with a digest for the
+// provider's next digest. It returns false if the alert is not deferred.
+func (p *BaseProvider) Defer(alert models.Alert) bool {
+	level := routingLevel(alert)
+	if !p.routes(alert.Component, level) {
+		return false
+	}
+	q := p.quietNow(alert.Component, level)
+	if q == nil || !q.digest {
+		return false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	alert.Value = fmt.Sprintf("%s (%s)", alert.Value, alert.Timestamp.Format("Mon 15:04"))
+	p.deferred = append(p.deferred, alert)
+	return true
+}
+
+// Digest returns the alerts deferred during quiet hours as a single
+// notification, once no quiet hours with a digest apply anymore (or when
+// forced, on shutdown)
+func (p *BaseProvider) Digest(force bool) (models.Alert, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.deferred) == 0 {
+		return models.Alert{}, false
+	}
+	if !force {
+		now := time.Now()
+		if p.now != nil {
+			now = p.now()
+		}
+		for _, q := range p.quiet {
+			if q.digest && q.window.Active(now) {
+				return models.Alert{}, false
+			}
+		}
+	}
+
+	digest := models.NewGroupAlert(p.deferred)
+	digest.Title = fmt.Sprintf("DIGEST : %d alert(s) during quiet hours", len(p.deferred))
+	p.deferred = nil
+	return digest, true
+}
+
+// routingLevel is the level an alert is routed with: recoveries and flapping
+// notifications are routed like the level they relate to
+func routingLevel(alert models.Alert) models.Severity {
+	if alert.IsRecovery() || alert.IsFlapping() {
+		return alert.PreviousLevel
+	}
+	return alert.Level
+}
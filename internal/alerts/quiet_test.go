@@ -0,0 +1,54 @@
+package alerts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Gu1llaum-3/tinymonitor/internal/config"
+	"github.com/Gu1llaum-3/tinymonitor/internal/models"
+)
+
+func TestQuietHours(t *testing.T) {
+	now := time.Date(2026, 3, 4, 3, 0, 0, 0, time.Local) // Wednesday
+	phone := &recordingProvider{BaseProvider: BaseProvider{
+		ProviderName: "phone",
+		Enabled:      true,
+		quiet: newQuietHours("phone", []config.QuietHours{
+			{Start: "22:00", End: "07:00", Levels: []string{"WARNING"}, Digest: true},
+			{Days: []string{"wed"}, Start: "00:00", End: "08:00", Rules: []string{"filesystem"}},
+		}),
+		now: func() time.Time { return now },
+	}}
+	mail := &recordingProvider{BaseProvider: BaseProvider{ProviderName: "mail", Enabled: true}}
+
+	if phone.ShouldSend("CPU", models.SeverityWarning) {
+		t.Error("Expected WARNING to be quiet at 3 a.m.")
+	}
+	if !phone.ShouldSend("CPU", models.SeverityCritical) {
+		t.Error("Expected CRITICAL to be sent at 3 a.m.")
+	}
+	if phone.ShouldSend("DISK:/", models.SeverityCritical) {
+		t.Error("Expected the filesystem rule to be quiet on Wednesday morning")
+	}
+
+	m := newTestManager(config.GroupingConfig{}, phone, mail)
+	m.SendAlert("CPU", models.SeverityWarning, "85%")
+	m.SendAlert("DISK:/", models.SeverityCritical, "97%") // Quiet without digest: dropped
+	m.SendRecovery("CPU", models.SeverityWarning, "40%")
+	m.ReleaseHeld()
+
+	now = time.Date(2026, 3, 4, 7, 30, 0, 0, time.Local)
+	m.ReleaseHeld()
+	m.Shutdown()
+
+	if len(mail.sent) != 3 {
+		t.Errorf("mail received %d notifications, want 3", len(mail.sent))
+	}
+	if len(phone.sent) != 1 {
+		t.Fatalf("phone received %d notifications, want the digest only", len(phone.sent))
+	}
+	digest := phone.sent[0]
+	if len(digest.Group) != 2 || digest.Group[0].Level != models.SeverityWarning || !digest.Group[1].IsRecovery() {
+		t.Errorf("Expected a digest of the CPU alert and its recovery, got %+v", digest)
+	}
+}
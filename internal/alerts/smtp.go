@@ -32,6 +32,8 @@ func NewSMTPProvider(cfg config.SMTPConfig) *SMTPProvider {
 			Enabled:      cfg.Enabled,
 			Levels:       cfg.Levels,
 			Rules:        cfg.Rules,
+			Individual:   cfg.IndividualAlerts,
+			quiet:        newQuietHours("smtp", cfg.QuietHours),
 		},
 		host:     cfg.Host,
 		port:     cfg.Port,
@@ -58,6 +60,12 @@ func (p *SMTPProvider) Send(alert models.Alert) error {
 	uptimePretty := utils.GetUptime()
 
 	subject := fmt.Sprintf("[%s] %s on %s - %s", alert.Level, alert.Component, hostname, alert.Value)
+	value := alert.Value
+	if alert.IsGroup() {
+		// The value spans several lines, keep it out of the header
+		subject = fmt.Sprintf("[%s] %s on %s", alert.Level, alert.Title, hostname)
+		value = strings.ReplaceAll(value, "\n", "<br>")
+	}
 
 	// Color based on level
 	var headerColor string
@@ -68,6 +76,10 @@ func (p *SMTPProvider) Send(alert models.Alert) error {
 		headerColor = "#FFA500"
 	case models.SeverityRecovery:
 		headerColor = "#00AA00"
+	case models.SeverityFlapping:
+		headerColor = "#8E44AD"
+	case models.SeverityStable:
+		headerColor = "#1E90FF"
 	default:
 		headerColor = "#333333"
 	}
@@ -91,7 +103,7 @@ func (p *SMTPProvider) Send(alert models.Alert) error {
 		</ul>
 	</body>
 	</html>`,
-		headerColor, alert.Title, alert.Component, alert.Value, headerColor, alert.Level,
+		headerColor, alert.Title, alert.Component, value, headerColor, alert.Level,
 		hostname, ipPrivate, ipPublic, loadAvg, uptimePretty, executionTime)
 
 	msg := fmt.Sprintf("From: %s\r\n"+
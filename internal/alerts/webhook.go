@@ -28,6 +28,8 @@ func NewWebhookProvider(cfg config.WebhookConfig) *WebhookProvider {
 			Enabled:      cfg.Enabled,
 			Levels:       cfg.Levels,
 			Rules:        cfg.Rules,
+			Individual:   cfg.IndividualAlerts,
+			quiet:        newQuietHours("webhook", cfg.QuietHours),
 		},
 		url:     cfg.URL,
 		headers: cfg.Headers,
@@ -54,6 +56,24 @@ func (p *WebhookProvider) Send(alert models.Alert) error {
 		alertData["previous_level"] = alert.PreviousLevel
 	}
 
+	// Add worst_level for flapping notifications
+	if alert.IsFlapping() {
+		alertData["worst_level"] = alert.PreviousLevel
+	}
+
+	// List the combined alerts of a grouped notification
+	if alert.IsGroup() {
+		alerts := make([]map[string]interface{}, 0, len(alert.Group))
+		for _, a := range alert.Group {
+			alerts = append(alerts, map[string]interface{}{
+				"level":     a.Level,
+				"component": a.Component,
+				"value":     a.Value,
+			})
+		}
+		alertData["alerts"] = alerts
+	}
+
 	payload := map[string]interface{}{
 		"timestamp": time.Now().Format(time.RFC3339),
 		"alert":     alertData,
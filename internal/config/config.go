@@ -4,24 +4,51 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
+
+	"github.com/Gu1llaum-3/tinymonitor/internal/expr"
+	"github.com/Gu1llaum-3/tinymonitor/internal/state"
 )
 
 // Config represents the main configuration
 type Config struct {
-	Refresh    int              `toml:"refresh"`
-	Cooldown   int              `toml:"cooldown"`
-	LogFile    string           `toml:"log_file"`
-	Load       LoadConfig       `toml:"load"`
-	CPU        MetricConfig     `toml:"cpu"`
-	Memory     MetricConfig     `toml:"memory"`
-	Filesystem FilesystemConfig `toml:"filesystem"`
-	Reboot     RebootConfig     `toml:"reboot"`
-	IO         IOConfig         `toml:"io"`
-	Alerts     AlertsConfig     `toml:"alerts"`
+	Refresh           int                     `toml:"refresh"`
+	Cooldown          int                     `toml:"cooldown"`
+	LogFile           string                  `toml:"log_file"`
+	StateDir          string                  `toml:"state_dir"`
+	Recovery          RecoveryConfig          `toml:"recovery"`
+	Flapping          FlappingConfig          `toml:"flapping"`
+	Maintenance       []MaintenanceWindow     `toml:"maintenance"`
+	Rules             []Rule                  `toml:"rule"`
+	Trends            []TrendConfig           `toml:"trend"`
+	Anomalies         []AnomalyConfig         `toml:"anomaly"`
+	Load              LoadConfig              `toml:"load"`
+	CPU               MetricConfig            `toml:"cpu"`
+	Memory            MetricConfig            `toml:"memory"`
+	Filesystem        FilesystemConfig        `toml:"filesystem"`
+	Reboot            RebootConfig            `toml:"reboot"`
+	IO                IOConfig                `toml:"io"`
+	Kernel            KernelConfig            `toml:"kernel"`
+	RAID              RAIDConfig              `toml:"raid"`
+	Limits            LimitsConfig            `toml:"limits"`
+	TCP               TCPConfig               `toml:"tcp"`
+	Temperature       TemperatureConfig       `toml:"temperature"`
+	Updates           UpdatesConfig           `toml:"updates"`
+	Uptime            UptimeConfig            `toml:"uptime"`
+	Cgroup            CgroupConfig            `toml:"cgroup"`
+	Containers        ContainersConfig        `toml:"containers"`
+	Files             FilesConfig             `toml:"files"`
+	NTP               NTPConfig               `toml:"ntp"`
+	HeartbeatListener HeartbeatListenerConfig `toml:"heartbeat_listener"`
+	Heartbeats        []HeartbeatConfig       `toml:"heartbeat"`
+	OutboundHeartbeat OutboundHeartbeatConfig `toml:"outbound_heartbeat"`
+	Alerts            AlertsConfig            `toml:"alerts"`
 }
 
 // MetricConfig represents configuration for a simple metric
@@ -32,6 +59,128 @@ type MetricConfig struct {
 	Duration int     `toml:"duration"`
 }
 
+// RecoveryRule controls how an alert clears. Once alerting, a value must drop
+// below the clear threshold to go back to a lower level: the explicit
+// WarningClear/CriticalClear, or the threshold minus Hysteresis percent. The
+// component must then stay OK for Duration seconds before RECOVERED is sent.
+type RecoveryRule struct {
+	Hysteresis    float64 `toml:"hysteresis"`
+	WarningClear  float64 `toml:"warning_clear"`
+	CriticalClear float64 `toml:"critical_clear"`
+	Duration      int     `toml:"duration"`
+}
+
+// RecoveryConfig represents the recovery settings applied by the monitor to
+// every collector. Rules are keyed like alert rules ("memory", "filesystem",
+// "load5"...); zero fields inherit the global values.
+type RecoveryConfig struct {
+	Hysteresis float64                 `toml:"hysteresis"`
+	Duration   int                     `toml:"duration"`
+	Rules      map[string]RecoveryRule `toml:"rules"`
+}
+
+// RuleFor returns the effective recovery rule for a configuration key
+func (c RecoveryConfig) RuleFor(key string) RecoveryRule {
+	rule := c.Rules[key]
+	if rule.Hysteresis == 0 {
+		rule.Hysteresis = c.Hysteresis
+	}
+	if rule.Duration == 0 {
+		rule.Duration = c.Duration
+	}
+	return rule
+}
+
+// FlappingConfig represents flap detection. A component changing state more
+// than Threshold times within Window seconds is flapping: a single FLAPPING
+// notification is sent, and a summary once no change happened for StableTime
+// seconds.
+type FlappingConfig struct {
+	Enabled    bool `toml:"enabled"`
+	Threshold  int  `toml:"threshold"`
+	Window     int  `toml:"window"`
+	StableTime int  `toml:"stable_time"`
+}
+
+// Rule is a composite alert rule: Expr is evaluated over the latest values of
+// the collectors after each check cycle (see internal/expr) and raises the
+// RULE:<Name> component at Severity while true for Duration seconds. Message
+// is the alert value; {COMPONENT} placeholders are replaced by the latest
+// value of the component.
+type Rule struct {
+	Name     string `toml:"name"`
+	Expr     string `toml:"expr"`
+	Severity string `toml:"severity"`
+	Duration int    `toml:"duration"`
+	Message  string `toml:"message"`
+}
+
+// TrendConfig is a rate-of-change threshold: the change of the value of the
+// matching components over the last Window seconds, in value units
+// ("absolute") or in percent of the older value ("percent"), going "up",
+// "down" or "both". It raises the TREND:<component> component.
+type TrendConfig struct {
+	Component string  `toml:"component"` // Glob pattern
+	Window    int     `toml:"window"`
+	Mode      string  `toml:"mode"`
+	Direction string  `toml:"direction"`
+	Warning   float64 `toml:"warning"`
+	Critical  float64 `toml:"critical"`
+	Duration  int     `toml:"duration"`
+}
+
+// AnomalyConfig is an adaptive threshold: the matching components learn a
+// baseline (EWMA mean and variance, per hour of the week when Seasonal) and
+// raise ANOMALY:<component> when the value deviates from it by more than
+// Warning / Critical standard deviations. Nothing is raised during the
+// Warmup seconds following the first sample of a baseline.
+type AnomalyConfig struct {
+	Component string  `toml:"component"` // Glob pattern
+	Seasonal  bool    `toml:"seasonal"`
+	HalfLife  int     `toml:"half_life"` // Seconds after which a sample weighs half, 0 = 3600 (4 weeks when seasonal)
+	Warning   float64 `toml:"warning"`
+	Critical  float64 `toml:"critical"`
+	Direction string  `toml:"direction"`
+	MinStdDev float64 `toml:"min_stddev"` // 0 = 1% of the mean
+	Duration  int     `toml:"duration"`
+	Warmup    int     `toml:"warmup"` // 0 = 86400
+}
+
+// MaintenanceWindow represents a recurring window during which matching
+// alerts are not delivered. Days are "mon".."sun" (empty = every day); Start
+// and End are "HH:MM" in TimeZone (empty = local time) and may wrap past
+// midnight. Components are globs ("DISK:*"); empty Components or Levels match
+// everything.
+type MaintenanceWindow struct {
+	Name       string   `toml:"name"`
+	Days       []string `toml:"days"`
+	Start      string   `toml:"start"`
+	End        string   `toml:"end"`
+	TimeZone   string   `toml:"timezone"`
+	Components []string `toml:"components"`
+	Levels     []string `toml:"levels"`
+}
+
+// Weekdays maps the day names accepted in maintenance windows
+var Weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ParseClock parses a "HH:MM" time of day into minutes since midnight
+func ParseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (expected HH:MM)", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
 // LoadWindowConfig configures alerting for a single load-average window.
 // Threshold overrides are optional: a zero value inherits the shared [load] default.
 type LoadWindowConfig struct {
@@ -80,19 +229,31 @@ func (c *LoadConfig) ThresholdsFor(w LoadWindowConfig) (warning, critical float6
 	return loadOverride(w.Warning, c.Warning), loadOverride(w.Critical, c.Critical)
 }
 
-// FilesystemConfig represents filesystem metric configuration
+// FilesystemConfig represents filesystem metric configuration.
+// ExpectedMounts lists mountpoints that must be present; DetectReadOnly alerts
+// when a mount seen read-write switches to read-only (e.g. ext4 errors=remount-ro).
 type FilesystemConfig struct {
-	Warning  float64  `toml:"warning"`
-	Critical float64  `toml:"critical"`
-	Enabled  bool     `toml:"enabled"`
-	Duration int      `toml:"duration"`
-	Exclude  []string `toml:"exclude"`
+	Warning        float64  `toml:"warning"`
+	Critical       float64  `toml:"critical"`
+	Enabled        bool     `toml:"enabled"`
+	Duration       int      `toml:"duration"`
+	Exclude        []string `toml:"exclude"`
+	ExpectedMounts []string `toml:"expected_mounts"`
+	DetectReadOnly bool     `toml:"detect_readonly"`
+	UsageTimeout   int      `toml:"usage_timeout"`
 }
 
-// RebootConfig represents reboot metric configuration
+// RebootConfig represents reboot metric configuration.
+// Besides the Debian flag file, it can compare the running kernel with the
+// newest installed one, ask needs-restarting (RHEL family) and report processes
+// still using deleted libraries. Expensive checks run every Interval seconds.
 type RebootConfig struct {
-	Enabled  bool `toml:"enabled"`
-	Duration int  `toml:"duration"`
+	Enabled         bool `toml:"enabled"`
+	Duration        int  `toml:"duration"`
+	KernelCheck     bool `toml:"kernel_check"`
+	NeedsRestarting bool `toml:"needs_restarting"`
+	DeletedLibs     bool `toml:"deleted_libs"`
+	Interval        int  `toml:"interval"`
 }
 
 // IOConfig represents I/O metric configuration
@@ -104,14 +265,277 @@ type IOConfig struct {
 	Duration int         `toml:"duration"`
 }
 
+// KernelConfig represents kernel event (OOM kill, kernel log errors) configuration.
+// The paths are configurable so the collector can be pointed at fixture files.
+type KernelConfig struct {
+	Enabled    bool     `toml:"enabled"`
+	Duration   int      `toml:"duration"`
+	VmstatPath string   `toml:"vmstat_path"`
+	Kmsg       bool     `toml:"kmsg"`
+	KmsgPath   string   `toml:"kmsg_path"`
+	Patterns   []string `toml:"patterns"`
+}
+
+// RAIDConfig represents software RAID (/proc/mdstat) metric configuration
+type RAIDConfig struct {
+	Enabled    bool   `toml:"enabled"`
+	Duration   int    `toml:"duration"`
+	MdstatPath string `toml:"mdstat_path"`
+}
+
+// LimitsConfig represents kernel table exhaustion configuration: system-wide
+// file handles, conntrack entries and PIDs, each as a percentage of its limit.
+// ProcRoot is configurable so the collector can run against a fake /proc tree.
+type LimitsConfig struct {
+	Enabled    bool    `toml:"enabled"`
+	Warning    float64 `toml:"warning"`
+	Critical   float64 `toml:"critical"`
+	Duration   int     `toml:"duration"`
+	ProcessFDs bool    `toml:"process_fds"`
+	ProcRoot   string  `toml:"proc_root"`
+}
+
+// TCPThreshold is a warning/critical pair for a TCP statistic. A zero value
+// disables that level; a statistic with both levels at zero is not reported.
+type TCPThreshold struct {
+	Warning  float64 `toml:"warning"`
+	Critical float64 `toml:"critical"`
+}
+
+// Active returns true if at least one level is configured
+func (t TCPThreshold) Active() bool {
+	return t.Warning > 0 || t.Critical > 0
+}
+
+// TCPConfig represents TCP connection state and socket statistics configuration.
+// Socket counts are absolute; listen overflows and retransmits are per-second
+// rates. Ports restricts socket counts to the given local ports.
+type TCPConfig struct {
+	Enabled         bool         `toml:"enabled"`
+	Duration        int          `toml:"duration"`
+	Ports           []int        `toml:"ports"`
+	ProcRoot        string       `toml:"proc_root"`
+	Established     TCPThreshold `toml:"established"`
+	CloseWait       TCPThreshold `toml:"close_wait"`
+	TimeWait        TCPThreshold `toml:"time_wait"`
+	ListenOverflows TCPThreshold `toml:"listen_overflows"`
+	Retransmits     TCPThreshold `toml:"retransmits"`
+}
+
+// SensorThreshold overrides the temperature thresholds (°C) of the sensors
+// matching a label pattern
+type SensorThreshold struct {
+	Warning  float64 `toml:"warning"`
+	Critical float64 `toml:"critical"`
+}
+
+// TemperatureConfig represents hardware temperature sensor configuration.
+// Global thresholds of 0 fall back to each sensor's own max/crit values.
+// Sensors maps label glob patterns (e.g. "coretemp/*") to per-sensor thresholds.
+type TemperatureConfig struct {
+	Enabled  bool                       `toml:"enabled"`
+	Warning  float64                    `toml:"warning"`
+	Critical float64                    `toml:"critical"`
+	Duration int                        `toml:"duration"`
+	Exclude  []string                   `toml:"exclude"`
+	Sensors  map[string]SensorThreshold `toml:"sensors"`
+	SysRoot  string                     `toml:"sys_root"`
+}
+
+// UpdatesConfig represents pending package updates configuration.
+// Manager is "auto" (detect apt, dnf or apk) or one of those names. Checks are
+// expensive, so results are cached for Interval seconds.
+type UpdatesConfig struct {
+	Enabled        bool   `toml:"enabled"`
+	Duration       int    `toml:"duration"`
+	Manager        string `toml:"manager"`
+	Interval       int    `toml:"interval"`
+	Warning        int    `toml:"warning"`
+	SecurityMaxAge int    `toml:"security_max_age"`
+}
+
+// UptimeConfig represents reboot detection and maximum uptime configuration.
+// The boot time is persisted in the state directory to detect reboots across
+// restarts. MaxDays of 0 disables the maximum uptime warning.
+type UptimeConfig struct {
+	Enabled  bool `toml:"enabled"`
+	Duration int  `toml:"duration"`
+	MaxDays  int  `toml:"max_days"`
+}
+
+// PercentThreshold is a warning/critical pair expressed in percent
+type PercentThreshold struct {
+	Warning  float64 `toml:"warning"`
+	Critical float64 `toml:"critical"`
+}
+
+// CgroupConfig represents cgroup (v2, with v1 fallback) resource configuration.
+// Paths are relative to the cgroup mount (e.g. "system.slice/nginx.service");
+// when empty, the cgroup TinyMonitor runs in is monitored. Memory thresholds
+// are a percentage of the memory limit, throttle thresholds a percentage of
+// time spent CPU-throttled. IO thresholds use the [io] syntax (e.g. "100M").
+type CgroupConfig struct {
+	Enabled    bool             `toml:"enabled"`
+	Duration   int              `toml:"duration"`
+	Paths      []string         `toml:"paths"`
+	Memory     PercentThreshold `toml:"memory"`
+	Throttle   PercentThreshold `toml:"throttle"`
+	IOWarning  interface{}      `toml:"io_warning"`
+	IOCritical interface{}      `toml:"io_critical"`
+	Root       string           `toml:"root"`
+}
+
+// ContainersConfig represents Docker/Podman container health configuration.
+// Containers are selected by name (glob patterns) and/or label ("key" or
+// "key=value"); with neither, all containers are watched. CPU and memory
+// thresholds are percentages (0 disables them, and the stats queries).
+type ContainersConfig struct {
+	Enabled  bool             `toml:"enabled"`
+	Duration int              `toml:"duration"`
+	Socket   string           `toml:"socket"`
+	Timeout  int              `toml:"timeout"`
+	Names    []string         `toml:"names"`
+	Labels   []string         `toml:"labels"`
+	CPU      PercentThreshold `toml:"cpu"`
+	Memory   PercentThreshold `toml:"memory"`
+}
+
+// FileCheck is a [[files.check]] entry. Path is a file, a directory (walked
+// recursively) or a glob. MaxAge (seconds) applies to the newest file, MaxSize
+// (bytes or a string such as "10G") to the total size. Zero values disable a
+// check. Level is the severity raised: "warning" (default) or "critical".
+type FileCheck struct {
+	Name     string      `toml:"name"`
+	Path     string      `toml:"path"`
+	MaxAge   int         `toml:"max_age"`
+	MaxSize  interface{} `toml:"max_size"`
+	MaxFiles int         `toml:"max_files"`
+	Level    string      `toml:"level"`
+}
+
+// FilesConfig represents directory size and file freshness configuration.
+// Paths are scanned every Interval seconds.
+type FilesConfig struct {
+	Enabled  bool        `toml:"enabled"`
+	Duration int         `toml:"duration"`
+	Interval int         `toml:"interval"`
+	Checks   []FileCheck `toml:"check"`
+}
+
+// NTPConfig represents clock synchronisation configuration. The kernel sync
+// status is always checked (Linux); when Server is set ("host" or
+// "host:port"), it is queried over SNTP every Interval seconds and the clock
+// offset compared with the thresholds, in milliseconds.
+type NTPConfig struct {
+	Enabled  bool    `toml:"enabled"`
+	Duration int     `toml:"duration"`
+	Server   string  `toml:"server"`
+	Interval int     `toml:"interval"`
+	Timeout  int     `toml:"timeout"`
+	Warning  float64 `toml:"warning"`
+	Critical float64 `toml:"critical"`
+}
+
+// HeartbeatListenerConfig represents the HTTP listener receiving heartbeat
+// pings (/ping/<name>, /ping/<name>/start, /ping/<name>/fail)
+type HeartbeatListenerConfig struct {
+	Enabled  bool   `toml:"enabled"`
+	Listen   string `toml:"listen"`
+	Duration int    `toml:"duration"`
+}
+
+// HeartbeatConfig is a [[heartbeat]] entry: a job expected to ping every
+// Period seconds. It is late (WARNING) after Period, and missing (CRITICAL)
+// after Period + Grace.
+type HeartbeatConfig struct {
+	Name   string `toml:"name"`
+	Period int    `toml:"period"`
+	Grace  int    `toml:"grace"`
+}
+
+// OutboundHeartbeatConfig represents the heartbeat sent to an external dead
+// man's switch (healthchecks.io, Uptime Kuma push monitor...) every Interval
+// seconds, as long as the monitoring loop keeps completing its checks
+type OutboundHeartbeatConfig struct {
+	Enabled  bool              `toml:"enabled"`
+	URL      string            `toml:"url"`
+	Interval int               `toml:"interval"`
+	Method   string            `toml:"method"`
+	Body     string            `toml:"body"`
+	Headers  map[string]string `toml:"headers"`
+	Timeout  int               `toml:"timeout"`
+}
+
 // AlertsConfig represents all alert providers configuration
 type AlertsConfig struct {
-	SendRecovery bool             `toml:"send_recovery"`
-	GoogleChat   GoogleChatConfig `toml:"google_chat"`
-	Ntfy         NtfyConfig       `toml:"ntfy"`
-	SMTP         SMTPConfig       `toml:"smtp"`
-	Webhook      WebhookConfig    `toml:"webhook"`
-	Gotify       GotifyConfig     `toml:"gotify"`
+	SendRecovery bool               `toml:"send_recovery"`
+	Grouping     GroupingConfig     `toml:"grouping"`
+	Escalation   []EscalationPolicy `toml:"escalation"`
+	Inhibit      []InhibitRule      `toml:"inhibit"`
+	GoogleChat   GoogleChatConfig   `toml:"google_chat"`
+	Ntfy         NtfyConfig         `toml:"ntfy"`
+	SMTP         SMTPConfig         `toml:"smtp"`
+	Webhook      WebhookConfig      `toml:"webhook"`
+	Gotify       GotifyConfig       `toml:"gotify"`
+}
+
+// GroupingConfig represents alert grouping. Alerts raised within GroupWait
+// seconds are combined into a single notification per provider; once a group
+// was sent, the next one waits until GroupInterval seconds have elapsed.
+// Providers with individual_alerts = true receive every alert separately.
+type GroupingConfig struct {
+	Enabled       bool `toml:"enabled"`
+	GroupWait     int  `toml:"group_wait"`
+	GroupInterval int  `toml:"group_interval"`
+}
+
+// EscalationPolicy notifies more providers the longer an incident lasts.
+// The first policy matching the component and level of an alert applies;
+// alerts without a policy use the global cooldown.
+type EscalationPolicy struct {
+	Name       string           `toml:"name"`
+	Components []string         `toml:"components"` // Glob patterns, empty = all
+	Levels     []string         `toml:"levels"`     // Empty = WARNING and CRITICAL
+	Steps      []EscalationStep `toml:"steps"`
+}
+
+// EscalationStep sends the alert to Providers once After seconds have
+// elapsed since the incident started, then every Repeat seconds (0 = once)
+// until it recovers
+type EscalationStep struct {
+	After     int      `toml:"after"`
+	Providers []string `toml:"providers"`
+	Repeat    int      `toml:"repeat"`
+}
+
+// InhibitRule suppresses the alerts of the Targets components while an alert
+// of the Source component fires (at one of SourceLevels, any if empty). An
+// inhibited alert still firing when the source resolves is delivered then.
+type InhibitRule struct {
+	Source       string   `toml:"source"`
+	SourceLevels []string `toml:"source_levels"`
+	Targets      []string `toml:"targets"`
+}
+
+// ruleNamePattern restricts rule names to what reads well in RULE:<name>
+var ruleNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ProviderNames lists the provider names escalation steps can target
+var ProviderNames = []string{"ntfy", "google_chat", "smtp", "webhook", "gotify"}
+
+// QuietHours is a schedule during which a provider does not send the
+// matching alerts (Levels and provider rule keys, empty = all), e.g. no
+// WARNING on phones at night. Days, Start, End and TimeZone work as in
+// maintenance windows. With Digest, suppressed alerts are sent as a single
+// digest when the quiet hours end instead of being dropped.
+type QuietHours struct {
+	Days     []string `toml:"days"`
+	Start    string   `toml:"start"`
+	End      string   `toml:"end"`
+	TimeZone string   `toml:"timezone"`
+	Levels   []string `toml:"levels"`
+	Rules    []string `toml:"rules"`
+	Digest   bool     `toml:"digest"`
 }
 
 // ProviderRules represents alert filtering rules
@@ -119,52 +543,62 @@ type ProviderRules map[string][]string
 
 // GoogleChatConfig represents Google Chat alert configuration
 type GoogleChatConfig struct {
-	Enabled    bool          `toml:"enabled"`
-	WebhookURL string        `toml:"webhook_url"`
-	Levels     []string      `toml:"levels"`
-	Rules      ProviderRules `toml:"rules"`
+	Enabled          bool          `toml:"enabled"`
+	WebhookURL       string        `toml:"webhook_url"`
+	Levels           []string      `toml:"levels"`
+	Rules            ProviderRules `toml:"rules"`
+	IndividualAlerts bool          `toml:"individual_alerts"`
+	QuietHours       []QuietHours  `toml:"quiet_hours"`
 }
 
 // NtfyConfig represents Ntfy alert configuration
 type NtfyConfig struct {
-	Enabled  bool          `toml:"enabled"`
-	TopicURL string        `toml:"topic_url"`
-	Token    string        `toml:"token"`
-	Levels   []string      `toml:"levels"`
-	Rules    ProviderRules `toml:"rules"`
+	Enabled          bool          `toml:"enabled"`
+	TopicURL         string        `toml:"topic_url"`
+	Token            string        `toml:"token"`
+	Levels           []string      `toml:"levels"`
+	Rules            ProviderRules `toml:"rules"`
+	IndividualAlerts bool          `toml:"individual_alerts"`
+	QuietHours       []QuietHours  `toml:"quiet_hours"`
 }
 
 // SMTPConfig represents SMTP alert configuration
 type SMTPConfig struct {
-	Enabled  bool          `toml:"enabled"`
-	Host     string        `toml:"host"`
-	Port     int           `toml:"port"`
-	User     string        `toml:"user"`
-	Password string        `toml:"password"`
-	FromAddr string        `toml:"from_addr"`
-	ToAddrs  []string      `toml:"to_addrs"`
-	UseTLS   bool          `toml:"use_tls"`
-	Levels   []string      `toml:"levels"`
-	Rules    ProviderRules `toml:"rules"`
+	Enabled          bool          `toml:"enabled"`
+	Host             string        `toml:"host"`
+	Port             int           `toml:"port"`
+	User             string        `toml:"user"`
+	Password         string        `toml:"password"`
+	FromAddr         string        `toml:"from_addr"`
+	ToAddrs          []string      `toml:"to_addrs"`
+	UseTLS           bool          `toml:"use_tls"`
+	Levels           []string      `toml:"levels"`
+	Rules            ProviderRules `toml:"rules"`
+	IndividualAlerts bool          `toml:"individual_alerts"`
+	QuietHours       []QuietHours  `toml:"quiet_hours"`
 }
 
 // WebhookConfig represents generic webhook alert configuration
 type WebhookConfig struct {
-	Enabled bool              `toml:"enabled"`
-	URL     string            `toml:"url"`
-	Headers map[string]string `toml:"headers"`
-	Timeout int               `toml:"timeout"`
-	Levels  []string          `toml:"levels"`
-	Rules   ProviderRules     `toml:"rules"`
+	Enabled          bool              `toml:"enabled"`
+	URL              string            `toml:"url"`
+	Headers          map[string]string `toml:"headers"`
+	Timeout          int               `toml:"timeout"`
+	Levels           []string          `toml:"levels"`
+	Rules            ProviderRules     `toml:"rules"`
+	IndividualAlerts bool              `toml:"individual_alerts"`
+	QuietHours       []QuietHours      `toml:"quiet_hours"`
 }
 
 // GotifyConfig represents Gotify alert configuration
 type GotifyConfig struct {
-	Enabled bool          `toml:"enabled"`
-	URL     string        `toml:"url"`
-	Token   string        `toml:"token"`
-	Levels  []string      `toml:"levels"`
-	Rules   ProviderRules `toml:"rules"`
+	Enabled          bool          `toml:"enabled"`
+	URL              string        `toml:"url"`
+	Token            string        `toml:"token"`
+	Levels           []string      `toml:"levels"`
+	Rules            ProviderRules `toml:"rules"`
+	IndividualAlerts bool          `toml:"individual_alerts"`
+	QuietHours       []QuietHours  `toml:"quiet_hours"`
 }
 
 // ValidationError represents a configuration validation error
@@ -194,6 +628,17 @@ func Default() *Config {
 		Refresh:  2,
 		Cooldown: 60,
 		LogFile:  "",
+		StateDir: state.DefaultDir,
+		Recovery: RecoveryConfig{
+			Hysteresis: 0,
+			Duration:   0,
+		},
+		Flapping: FlappingConfig{
+			Enabled:    false,
+			Threshold:  5,
+			Window:     600,
+			StableTime: 600,
+		},
 		Load: LoadConfig{
 			Enabled:       true,
 			Auto:          true,
@@ -226,17 +671,145 @@ func Default() *Config {
 			Enabled:  true,
 			Duration: 300,
 			Exclude:  []string{},
+			// Mount checks: no expected mounts by default, read-only
+			// remounts always detected, usage calls bounded to 5s so a hung
+			// NFS share cannot block the monitoring loop.
+			ExpectedMounts: []string{},
+			DetectReadOnly: true,
+			UsageTimeout:   5,
 		},
 		Reboot: RebootConfig{
-			Enabled:  true,
-			Duration: 0,
+			Enabled:         true,
+			Duration:        0,
+			KernelCheck:     true,
+			NeedsRestarting: true,
+			DeletedLibs:     false,
+			Interval:        300,
 		},
 		IO: IOConfig{
 			Enabled:  true,
 			Duration: 120,
 		},
+		// Kernel events are opt-in: reading /dev/kmsg requires root (or
+		// CAP_SYSLOG) on most distributions.
+		Kernel: KernelConfig{
+			Enabled:    false,
+			Duration:   0,
+			VmstatPath: "/proc/vmstat",
+			Kmsg:       false,
+			KmsgPath:   "/dev/kmsg",
+			Patterns: []string{
+				`blocked for more than \d+ seconds`,
+				`EXT4-fs error`,
+				`XFS \(.*\): .*(error|Corruption)`,
+				`NETDEV WATCHDOG`,
+				`[Rr]eset adapter`,
+			},
+		},
+		// Software RAID is opt-in: most hosts have no md arrays.
+		RAID: RAIDConfig{
+			Enabled:    false,
+			Duration:   0,
+			MdstatPath: "/proc/mdstat",
+		},
+		Limits: LimitsConfig{
+			Enabled:    false,
+			Warning:    80,
+			Critical:   95,
+			Duration:   60,
+			ProcessFDs: false,
+			ProcRoot:   "/proc",
+		},
+		TCP: TCPConfig{
+			Enabled:         false,
+			Duration:        60,
+			Ports:           []int{},
+			ProcRoot:        "/proc",
+			CloseWait:       TCPThreshold{Warning: 500, Critical: 2000},
+			TimeWait:        TCPThreshold{Warning: 20000, Critical: 50000},
+			ListenOverflows: TCPThreshold{Warning: 1, Critical: 10},
+		},
+		// Temperature thresholds default to each sensor's own limits.
+		Temperature: TemperatureConfig{
+			Enabled:  false,
+			Warning:  0,
+			Critical: 0,
+			Duration: 60,
+			Exclude:  []string{},
+			SysRoot:  "/sys",
+		},
+		// Pending updates: refreshed every 6 hours, CRITICAL when a security
+		// update has been pending for a week.
+		Updates: UpdatesConfig{
+			Enabled:        false,
+			Duration:       0,
+			Manager:        "auto",
+			Interval:       21600,
+			Warning:        0,
+			SecurityMaxAge: 7,
+		},
+		Uptime: UptimeConfig{
+			Enabled:  false,
+			Duration: 0,
+			MaxDays:  0,
+		},
+		// Cgroup limits are opt-in: only meaningful for containers and
+		// services with resource limits.
+		Cgroup: CgroupConfig{
+			Enabled:  false,
+			Duration: 60,
+			Paths:    []string{},
+			Memory:   PercentThreshold{Warning: 80, Critical: 95},
+			Throttle: PercentThreshold{Warning: 25, Critical: 50},
+			Root:     "/sys/fs/cgroup",
+		},
+		// Containers: Docker socket by default, Podman users point socket
+		// at /run/podman/podman.sock. Resource thresholds are opt-in.
+		Containers: ContainersConfig{
+			Enabled:  false,
+			Duration: 0,
+			Socket:   "/var/run/docker.sock",
+			Timeout:  10,
+			Names:    []string{},
+			Labels:   []string{},
+		},
+		Files: FilesConfig{
+			Enabled:  false,
+			Duration: 0,
+			Interval: 60,
+			Checks:   []FileCheck{},
+		},
+		// Clock: kernel sync status only until a server is configured.
+		// Offsets in milliseconds.
+		NTP: NTPConfig{
+			Enabled:  false,
+			Duration: 300,
+			Server:   "",
+			Interval: 300,
+			Timeout:  5,
+			Warning:  100,
+			Critical: 1000,
+		},
+		// Listen on localhost only: cron jobs run on the same host
+		HeartbeatListener: HeartbeatListenerConfig{
+			Enabled:  false,
+			Listen:   "127.0.0.1:8910",
+			Duration: 0,
+		},
+		Heartbeats: []HeartbeatConfig{},
+		OutboundHeartbeat: OutboundHeartbeatConfig{
+			Enabled:  false,
+			Interval: 60,
+			Method:   "GET",
+			Timeout:  10,
+		},
 		Alerts: AlertsConfig{
 			SendRecovery: true,
+			Grouping: GroupingConfig{
+				Enabled:       false,
+				GroupWait:     10,
+				GroupInterval: 60,
+			},
 			GoogleChat: GoogleChatConfig{
 				Enabled: false,
 			},
@@ -337,6 +910,51 @@ func (c *Config) Validate() ValidationErrors {
 	if c.Cooldown < -1 {
 		errs = append(errs, ValidationError{"cooldown", "must be >= -1 (-1 = alert once per incident)"})
 	}
+	errs = append(errs, validateRecovery("recovery", RecoveryRule{Hysteresis: c.Recovery.Hysteresis, Duration: c.Recovery.Duration})...)
+	for key, rule := range c.Recovery.Rules {
+		errs = append(errs, validateRecovery("recovery.rules."+key, rule)...)
+	}
+	for i, window := range c.Maintenance {
+		errs = append(errs, validateMaintenance(fmt.Sprintf("maintenance[%d]", i), window)...)
+	}
+	ruleNames := make(map[string]bool)
+	for i, rule := range c.Rules {
+		field := fmt.Sprintf("rule[%d]", i)
+		if !ruleNamePattern.MatchString(rule.Name) {
+			errs = append(errs, ValidationError{field + ".name", "required, letters, digits, '_' and '-' only"})
+		} else if ruleNames[rule.Name] {
+			errs = append(errs, ValidationError{field + ".name", fmt.Sprintf("duplicate name %q", rule.Name)})
+		}
+		ruleNames[rule.Name] = true
+		if _, err := expr.Parse(rule.Expr); err != nil {
+			errs = append(errs, ValidationError{field + ".expr", err.Error()})
+		}
+		switch strings.ToUpper(rule.Severity) {
+		case "", "WARNING", "CRITICAL":
+		default:
+			errs = append(errs, ValidationError{field + ".severity", fmt.Sprintf("must be WARNING or CRITICAL (got %q)", rule.Severity)})
+		}
+		if rule.Duration < 0 {
+			errs = append(errs, ValidationError{field + ".duration", "must be >= 0"})
+		}
+	}
+	for i, trend := range c.Trends {
+		errs = append(errs, c.validateTrend(fmt.Sprintf("trend[%d]", i), trend)...)
+	}
+	for i, anomaly := range c.Anomalies {
+		errs = append(errs, validateAnomaly(fmt.Sprintf("anomaly[%d]", i), anomaly)...)
+	}
+	if c.Flapping.Enabled {
+		if c.Flapping.Threshold < 2 {
+			errs = append(errs, ValidationError{"flapping.threshold", "must be at least 2"})
+		}
+		if c.Flapping.Window <= 0 {
+			errs = append(errs, ValidationError{"flapping.window", "must be greater than 0"})
+		}
+		if c.Flapping.StableTime <= 0 {
+			errs = append(errs, ValidationError{"flapping.stable_time", "must be greater than 0"})
+		}
+	}
 
 	// CPU
 	if c.CPU.Enabled {
@@ -351,6 +969,17 @@ func (c *Config) Validate() ValidationErrors {
 	// Filesystem
 	if c.Filesystem.Enabled {
 		errs = append(errs, validateThresholds("filesystem", c.Filesystem.Warning, c.Filesystem.Critical)...)
+		if c.Filesystem.UsageTimeout <= 0 {
+			errs = append(errs, ValidationError{"filesystem.usage_timeout", "must be greater than 0"})
+		}
+		for i, mount := range c.Filesystem.ExpectedMounts {
+			if !strings.HasPrefix(mount, "/") {
+				errs = append(errs, ValidationError{
+					Field:   fmt.Sprintf("filesystem.expected_mounts[%d]", i),
+					Message: fmt.Sprintf("must be an absolute path (got %q)", mount),
+				})
+			}
+		}
 	}
 
 	// Load validation: each enabled window is checked against its effective
@@ -383,6 +1012,257 @@ func (c *Config) Validate() ValidationErrors {
 		}
 	}
 
+	// Reboot
+	if c.Reboot.Enabled && (c.Reboot.NeedsRestarting || c.Reboot.DeletedLibs) && c.Reboot.Interval <= 0 {
+		errs = append(errs, ValidationError{"reboot.interval", "must be greater than 0"})
+	}
+
+	// Kernel
+	if c.Kernel.Enabled {
+		if c.Kernel.Duration < 0 {
+			errs = append(errs, ValidationError{"kernel.duration", "must be >= 0"})
+		}
+		if c.Kernel.Kmsg {
+			for i, pattern := range c.Kernel.Patterns {
+				if _, err := regexp.Compile(pattern); err != nil {
+					errs = append(errs, ValidationError{
+						Field:   fmt.Sprintf("kernel.patterns[%d]", i),
+						Message: fmt.Sprintf("invalid regular expression: %v", err),
+					})
+				}
+			}
+		}
+	}
+
+	// RAID
+	if c.RAID.Enabled && c.RAID.Duration < 0 {
+		errs = append(errs, ValidationError{"raid.duration", "must be >= 0"})
+	}
+
+	// Kernel table limits
+	if c.Limits.Enabled {
+		errs = append(errs, validateThresholds("limits", c.Limits.Warning, c.Limits.Critical)...)
+	}
+
+	// TCP
+	if c.TCP.Enabled {
+		for i, port := range c.TCP.Ports {
+			if port < 1 || port > 65535 {
+				errs = append(errs, ValidationError{fmt.Sprintf("tcp.ports[%d]", i), "must be between 1 and 65535"})
+			}
+		}
+		validateTCPThreshold := func(name string, t TCPThreshold) {
+			if t.Warning < 0 || t.Critical < 0 {
+				errs = append(errs, ValidationError{"tcp." + name, "thresholds must be >= 0"})
+			}
+			if t.Warning > 0 && t.Critical > 0 && t.Warning >= t.Critical {
+				errs = append(errs, ValidationError{"tcp." + name, "warning must be less than critical"})
+			}
+		}
+		validateTCPThreshold("established", c.TCP.Established)
+		validateTCPThreshold("close_wait", c.TCP.CloseWait)
+		validateTCPThreshold("time_wait", c.TCP.TimeWait)
+		validateTCPThreshold("listen_overflows", c.TCP.ListenOverflows)
+		validateTCPThreshold("retransmits", c.TCP.Retransmits)
+	}
+
+	// Temperature
+	if c.Temperature.Enabled {
+		validateTemperature := func(name string, warning, critical float64) {
+			if warning < 0 || critical < 0 {
+				errs = append(errs, ValidationError{name, "thresholds must be >= 0"})
+			}
+			if warning > 0 && critical > 0 && warning >= critical {
+				errs = append(errs, ValidationError{name, fmt.Sprintf("warning (%.1f) must be less than critical (%.1f)", warning, critical)})
+			}
+		}
+		validateTemperature("temperature", c.Temperature.Warning, c.Temperature.Critical)
+		for pattern, t := range c.Temperature.Sensors {
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				errs = append(errs, ValidationError{"temperature.sensors." + pattern, "invalid pattern"})
+			}
+			validateTemperature("temperature.sensors."+pattern, t.Warning, t.Critical)
+		}
+	}
+
+	// Updates
+	if c.Updates.Enabled {
+		switch c.Updates.Manager {
+		case "auto", "apt", "dnf", "apk":
+		default:
+			errs = append(errs, ValidationError{"updates.manager", fmt.Sprintf("must be auto, apt, dnf or apk (got %q)", c.Updates.Manager)})
+		}
+		if c.Updates.Interval < 60 {
+			errs = append(errs, ValidationError{"updates.interval", "must be at least 60 seconds"})
+		}
+		if c.Updates.Warning < 0 {
+			errs = append(errs, ValidationError{"updates.warning", "must be >= 0"})
+		}
+		if c.Updates.SecurityMaxAge < 0 {
+			errs = append(errs, ValidationError{"updates.security_max_age", "must be >= 0"})
+		}
+	}
+
+	// Uptime
+	if c.Uptime.Enabled && c.Uptime.MaxDays < 0 {
+		errs = append(errs, ValidationError{"uptime.max_days", "must be >= 0"})
+	}
+
+	// Cgroup
+	if c.Cgroup.Enabled {
+		errs = append(errs, validateThresholds("cgroup.memory", c.Cgroup.Memory.Warning, c.Cgroup.Memory.Critical)...)
+		errs = append(errs, validateThresholds("cgroup.throttle", c.Cgroup.Throttle.Warning, c.Cgroup.Throttle.Critical)...)
+		for i, path := range c.Cgroup.Paths {
+			if strings.Contains(path, "..") {
+				errs = append(errs, ValidationError{fmt.Sprintf("cgroup.paths[%d]", i), "must not contain '..'"})
+			}
+		}
+	}
+
+	// Containers
+	if c.Containers.Enabled {
+		if c.Containers.Socket == "" {
+			errs = append(errs, ValidationError{"containers.socket", "required when containers is enabled"})
+		}
+		if c.Containers.Timeout <= 0 {
+			errs = append(errs, ValidationError{"containers.timeout", "must be greater than 0"})
+		}
+		for i, pattern := range c.Containers.Names {
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				errs = append(errs, ValidationError{fmt.Sprintf("containers.names[%d]", i), "invalid pattern"})
+			}
+		}
+		if c.Containers.CPU != (PercentThreshold{}) {
+			errs = append(errs, validateThresholds("containers.cpu", c.Containers.CPU.Warning, c.Containers.CPU.Critical)...)
+		}
+		if c.Containers.Memory != (PercentThreshold{}) {
+			errs = append(errs, validateThresholds("containers.memory", c.Containers.Memory.Warning, c.Containers.Memory.Critical)...)
+		}
+	}
+
+	// Files
+	if c.Files.Enabled {
+		if c.Files.Interval <= 0 {
+			errs = append(errs, ValidationError{"files.interval", "must be greater than 0"})
+		}
+		names := make(map[string]bool)
+		for i, check := range c.Files.Checks {
+			field := fmt.Sprintf("files.check[%d]", i)
+			if check.Path == "" {
+				errs = append(errs, ValidationError{field + ".path", "required"})
+			} else if _, err := filepath.Match(check.Path, ""); err != nil {
+				errs = append(errs, ValidationError{field + ".path", "invalid pattern"})
+			}
+			name := check.Name
+			if name == "" {
+				name = check.Path
+			}
+			if names[name] {
+				errs = append(errs, ValidationError{field + ".name", fmt.Sprintf("duplicate name %q", name)})
+			}
+			names[name] = true
+			if check.MaxAge < 0 || check.MaxFiles < 0 {
+				errs = append(errs, ValidationError{field, "max_age and max_files must be >= 0"})
+			}
+			switch check.MaxSize.(type) {
+			case nil, string, int64, float64:
+			default:
+				errs = append(errs, ValidationError{field + ".max_size", "must be a number of bytes or a string such as \"10G\""})
+			}
+			if check.MaxAge == 0 && check.MaxSize == nil && check.MaxFiles == 0 {
+				errs = append(errs, ValidationError{field, "at least one of max_age, max_size or max_files is required"})
+			}
+			switch check.Level {
+			case "", "warning", "critical":
+			default:
+				errs = append(errs, ValidationError{field + ".level", fmt.Sprintf("must be warning or critical (got %q)", check.Level)})
+			}
+		}
+	}
+
+	// NTP
+	if c.NTP.Enabled {
+		if c.NTP.Warning <= 0 || c.NTP.Critical <= 0 {
+			errs = append(errs, ValidationError{"ntp", "warning and critical must be greater than 0"})
+		} else if c.NTP.Warning >= c.NTP.Critical {
+			errs = append(errs, ValidationError{"ntp", fmt.Sprintf("warning (%.1f) must be less than critical (%.1f)", c.NTP.Warning, c.NTP.Critical)})
+		}
+		if c.NTP.Server != "" {
+			// Public servers rate-limit clients polling faster than this
+			if c.NTP.Interval < 16 {
+				errs = append(errs, ValidationError{"ntp.interval", "must be at least 16 seconds"})
+			}
+			if c.NTP.Timeout <= 0 {
+				errs = append(errs, ValidationError{"ntp.timeout", "must be greater than 0"})
+			}
+		}
+	}
+
+	// Heartbeats
+	if c.HeartbeatListener.Enabled {
+		if c.HeartbeatListener.Listen == "" {
+			errs = append(errs, ValidationError{"heartbeat_listener.listen", "required when heartbeat_listener is enabled"})
+		}
+		if len(c.Heartbeats) == 0 {
+			errs = append(errs, ValidationError{"heartbeat", "at least one [[heartbeat]] is required when heartbeat_listener is enabled"})
+		}
+	}
+	heartbeatNames := make(map[string]bool)
+	for i, hb := range c.Heartbeats {
+		field := fmt.Sprintf("heartbeat[%d]", i)
+		if hb.Name == "" || strings.ContainsAny(hb.Name, "/?# ") {
+			errs = append(errs, ValidationError{field + ".name", "required, without '/', '?', '#' or spaces"})
+		} else if heartbeatNames[hb.Name] {
+			errs = append(errs, ValidationError{field + ".name", fmt.Sprintf("duplicate name %q", hb.Name)})
+		}
+		heartbeatNames[hb.Name] = true
+		if hb.Period <= 0 {
+			errs = append(errs, ValidationError{field + ".period", "must be greater than 0"})
+		}
+		if hb.Grace < 0 {
+			errs = append(errs, ValidationError{field + ".grace", "must be >= 0"})
+		}
+	}
+
+	// Outbound heartbeat
+	if c.OutboundHeartbeat.Enabled {
+		if c.OutboundHeartbeat.URL == "" {
+			errs = append(errs, ValidationError{"outbound_heartbeat.url", "required when outbound_heartbeat is enabled"})
+		}
+		// A heartbeat is only sent after a completed check cycle
+		if c.OutboundHeartbeat.Interval < c.Refresh {
+			errs = append(errs, ValidationError{"outbound_heartbeat.interval", fmt.Sprintf("must be at least refresh (%ds)", c.Refresh)})
+		}
+		switch strings.ToUpper(c.OutboundHeartbeat.Method) {
+		case "GET", "POST", "PUT", "HEAD":
+		default:
+			errs = append(errs, ValidationError{"outbound_heartbeat.method", fmt.Sprintf("must be GET, POST, PUT or HEAD (got %q)", c.OutboundHeartbeat.Method)})
+		}
+		if c.OutboundHeartbeat.Timeout <= 0 {
+			errs = append(errs, ValidationError{"outbound_heartbeat.timeout", "must be greater than 0"})
+		}
+	}
+
+	// Alert grouping
+	if c.Alerts.Grouping.Enabled {
+		if c.Alerts.Grouping.GroupWait <= 0 {
+			errs = append(errs, ValidationError{"alerts.grouping.group_wait", "must be greater than 0"})
+		}
+		if c.Alerts.Grouping.GroupInterval < c.Alerts.Grouping.GroupWait {
+			errs = append(errs, ValidationError{"alerts.grouping.group_interval", fmt.Sprintf("must be at least group_wait (%ds)", c.Alerts.Grouping.GroupWait)})
+		}
+	}
+
+	// Escalation policies
+	for i, policy := range c.Alerts.Escalation {
+		errs = append(errs, validateEscalation(fmt.Sprintf("alerts.escalation[%d]", i), policy)...)
+	}
+
+	// Inhibition rules
+	for i, rule := range c.Alerts.Inhibit {
+		errs = append(errs, validateInhibit(fmt.Sprintf("alerts.inhibit[%d]", i), rule)...)
+	}
+
 	// Alert providers
 	if c.Alerts.GoogleChat.Enabled {
 		if c.Alerts.GoogleChat.WebhookURL == "" {
@@ -435,6 +1315,29 @@ func (c *Config) Validate() ValidationErrors {
 		}
 	}
 
+	// Quiet hours
+	for _, provider := range []struct {
+		name       string
+		quietHours []QuietHours
+	}{
+		{"google_chat", c.Alerts.GoogleChat.QuietHours},
+		{"ntfy", c.Alerts.Ntfy.QuietHours},
+		{"smtp", c.Alerts.SMTP.QuietHours},
+		{"webhook", c.Alerts.Webhook.QuietHours},
+		{"gotify", c.Alerts.Gotify.QuietHours},
+	} {
+		for i, quiet := range provider.quietHours {
+			name := fmt.Sprintf("alerts.%s.quiet_hours[%d]", provider.name, i)
+			errs = append(errs, validateMaintenance(name, MaintenanceWindow{
+				Days:     quiet.Days,
+				Start:    quiet.Start,
+				End:      quiet.End,
+				TimeZone: quiet.TimeZone,
+				Levels:   quiet.Levels,
+			})...)
+		}
+	}
+
 	return errs
 }
 
@@ -463,6 +1366,181 @@ func validateThresholds(name string, warning, critical float64) ValidationErrors
 	return errs
 }
 
+func validateRecovery(name string, rule RecoveryRule) ValidationErrors {
+	var errs ValidationErrors
+
+	if rule.Hysteresis < 0 || rule.Hysteresis >= 100 {
+		errs = append(errs, ValidationError{
+			Field:   fmt.Sprintf("%s.hysteresis", name),
+			Message: fmt.Sprintf("must be between 0 and 100 percent (got %.1f)", rule.Hysteresis),
+		})
+	}
+	if rule.WarningClear < 0 || rule.CriticalClear < 0 {
+		errs = append(errs, ValidationError{
+			Field:   name,
+			Message: "warning_clear and critical_clear must be >= 0",
+		})
+	}
+	if rule.Duration < 0 {
+		errs = append(errs, ValidationError{
+			Field:   fmt.Sprintf("%s.duration", name),
+			Message: "must be >= 0",
+		})
+	}
+
+	return errs
+}
+
+func validateMaintenance(name string, window MaintenanceWindow) ValidationErrors {
+	var errs ValidationErrors
+
+	for _, day := range window.Days {
+		if _, ok := Weekdays[strings.ToLower(day)]; !ok {
+			errs = append(errs, ValidationError{name + ".days", fmt.Sprintf("unknown day %q (expected mon, tue, wed, thu, fri, sat or sun)", day)})
+		}
+	}
+	for _, field := range []struct{ key, value string }{{"start", window.Start}, {"end", window.End}} {
+		if _, err := ParseClock(field.value); err != nil {
+			errs = append(errs, ValidationError{name + "." + field.key, err.Error()})
+		}
+	}
+	if window.Start != "" && window.Start == window.End {
+		errs = append(errs, ValidationError{name, "start and end must differ"})
+	}
+	if window.TimeZone != "" {
+		if _, err := time.LoadLocation(window.TimeZone); err != nil {
+			errs = append(errs, ValidationError{name + ".timezone", fmt.Sprintf("unknown time zone %q", window.TimeZone)})
+		}
+	}
+	for _, level := range window.Levels {
+		switch strings.ToUpper(level) {
+		case "WARNING", "CRITICAL":
+		default:
+			errs = append(errs, ValidationError{name + ".levels", fmt.Sprintf("must be WARNING or CRITICAL (got %q)", level)})
+		}
+	}
+
+	return errs
+}
+
+func validateEscalation(name string, policy EscalationPolicy) ValidationErrors {
+	var errs ValidationErrors
+
+	for _, pattern := range policy.Components {
+		if pattern == "" {
+			errs = append(errs, ValidationError{name + ".components", "empty component pattern"})
+		}
+	}
+	for _, level := range policy.Levels {
+		switch strings.ToUpper(level) {
+		case "WARNING", "CRITICAL":
+		default:
+			errs = append(errs, ValidationError{name + ".levels", fmt.Sprintf("must be WARNING or CRITICAL (got %q)", level)})
+		}
+	}
+	if len(policy.Steps) == 0 {
+		errs = append(errs, ValidationError{name + ".steps", "at least one step is required"})
+	}
+	for i, step := range policy.Steps {
+		field := fmt.Sprintf("%s.steps[%d]", name, i)
+		if step.After < 0 {
+			errs = append(errs, ValidationError{field + ".after", "must be >= 0"})
+		}
+		if step.Repeat < 0 {
+			errs = append(errs, ValidationError{field + ".repeat", "must be >= 0 (0 = notify once)"})
+		}
+		if len(step.Providers) == 0 {
+			errs = append(errs, ValidationError{field + ".providers", "at least one provider is required"})
+		}
+		for _, provider := range step.Providers {
+			if !slices.Contains(ProviderNames, provider) {
+				errs = append(errs, ValidationError{field + ".providers", fmt.Sprintf("unknown provider %q (expected %s)", provider, strings.Join(ProviderNames, ", "))})
+			}
+		}
+	}
+
+	return errs
+}
+
+func (c *Config) validateTrend(name string, trend TrendConfig) ValidationErrors {
+	var errs ValidationErrors
+
+	if trend.Component == "" {
+		errs = append(errs, ValidationError{name + ".component", "required"})
+	}
+	if trend.Window < c.Refresh {
+		errs = append(errs, ValidationError{name + ".window", fmt.Sprintf("must be at least refresh (%ds)", c.Refresh)})
+	}
+	switch trend.Mode {
+	case "", "absolute", "percent":
+	default:
+		errs = append(errs, ValidationError{name + ".mode", fmt.Sprintf("must be absolute or percent (got %q)", trend.Mode)})
+	}
+	switch trend.Direction {
+	case "", "up", "down", "both":
+	default:
+		errs = append(errs, ValidationError{name + ".direction", fmt.Sprintf("must be up, down or both (got %q)", trend.Direction)})
+	}
+	if trend.Warning < 0 || trend.Critical < 0 || (trend.Warning == 0 && trend.Critical == 0) {
+		errs = append(errs, ValidationError{name, "warning and/or critical must be set, greater than 0"})
+	} else if trend.Warning > 0 && trend.Critical > 0 && trend.Critical < trend.Warning {
+		errs = append(errs, ValidationError{name + ".critical", "must be >= warning"})
+	}
+	if trend.Duration < 0 {
+		errs = append(errs, ValidationError{name + ".duration", "must be >= 0"})
+	}
+
+	return errs
+}
+
+func validateAnomaly(name string, anomaly AnomalyConfig) ValidationErrors {
+	var errs ValidationErrors
+
+	if anomaly.Component == "" {
+		errs = append(errs, ValidationError{name + ".component", "required"})
+	}
+	switch anomaly.Direction {
+	case "", "up", "down", "both":
+	default:
+		errs = append(errs, ValidationError{name + ".direction", fmt.Sprintf("must be up, down or both (got %q)", anomaly.Direction)})
+	}
+	if anomaly.Warning < 0 || anomaly.Critical < 0 || (anomaly.Warning == 0 && anomaly.Critical == 0) {
+		errs = append(errs, ValidationError{name, "warning and/or critical must be set, greater than 0 (standard deviations)"})
+	} else if anomaly.Warning > 0 && anomaly.Critical > 0 && anomaly.Critical < anomaly.Warning {
+		errs = append(errs, ValidationError{name + ".critical", "must be >= warning"})
+	}
+	if anomaly.HalfLife < 0 || anomaly.Warmup < 0 || anomaly.Duration < 0 || anomaly.MinStdDev < 0 {
+		errs = append(errs, ValidationError{name, "half_life, warmup, duration and min_stddev must be >= 0"})
+	}
+
+	return errs
+}
+
+func validateInhibit(name string, rule InhibitRule) ValidationErrors {
+	var errs ValidationErrors
+
+	if rule.Source == "" {
+		errs = append(errs, ValidationError{name + ".source", "required"})
+	}
+	for _, level := range rule.SourceLevels {
+		switch strings.ToUpper(level) {
+		case "WARNING", "CRITICAL":
+		default:
+			errs = append(errs, ValidationError{name + ".source_levels", fmt.Sprintf("must be WARNING or CRITICAL (got %q)", level)})
+		}
+	}
+	if len(rule.Targets) == 0 {
+		errs = append(errs, ValidationError{name + ".targets", "at least one target pattern is required"})
+	}
+	for _, pattern := range rule.Targets {
+		if pattern == "" {
+			errs = append(errs, ValidationError{name + ".targets", "empty component pattern"})
+		}
+	}
+
+	return errs
+}
+
 func getCurrentDir() string {
 	dir, err := os.Getwd()
 	if err != nil {
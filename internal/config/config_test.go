@@ -290,6 +290,178 @@ enabled = false
 			expectError: true,
 			errorField:  "load",
 		},
+		{
+			name: "kernel invalid pattern",
+			config: `
+refresh = 5
+cooldown = 60
+
+[kernel]
+enabled = true
+kmsg = true
+patterns = ['EXT4-fs error', '(unclosed']
+`,
+			expectError: true,
+			errorField:  "kernel.patterns[1]",
+		},
+		{
+			name: "files check without limits",
+			config: `
+refresh = 5
+cooldown = 60
+
+[files]
+enabled = true
+
+[[files.check]]
+name = "backups"
+path = "/srv/backups"
+max_size = "10G"
+
+[[files.check]]
+name = "inbox"
+path = "/srv/inbox"
+`,
+			expectError: true,
+			errorField:  "files.check[1]",
+		},
+		{
+			name: "recovery hysteresis out of range",
+			config: `
+refresh = 5
+cooldown = 60
+
+[recovery]
+hysteresis = 5
+
+[recovery.rules.memory]
+hysteresis = 150
+`,
+			expectError: true,
+			errorField:  "recovery.rules.memory.hysteresis",
+		},
+		{
+			name: "maintenance window with invalid day and time zone",
+			config: `
+refresh = 5
+cooldown = 60
+
+[[maintenance]]
+days = ["sat", "someday"]
+start = "22:00"
+end = "02:00"
+timezone = "Mars/Olympus"
+`,
+			expectError: true,
+			errorField:  "maintenance[0].days",
+		},
+		{
+			name: "grouping interval shorter than wait",
+			config: `
+refresh = 5
+cooldown = 60
+
+[alerts.grouping]
+enabled = true
+group_wait = 30
+group_interval = 10
+`,
+			expectError: true,
+			errorField:  "alerts.grouping.group_interval",
+		},
+		{
+			name: "escalation step with unknown provider",
+			config: `
+refresh = 5
+cooldown = 60
+
+[[alerts.escalation]]
+name = "critical"
+levels = ["CRITICAL"]
+
+  [[alerts.escalation.steps]]
+  after = 0
+  providers = ["ntfy"]
+
+  [[alerts.escalation.steps]]
+  after = 1800
+  providers = ["pagerduty"]
+`,
+			expectError: true,
+			errorField:  "alerts.escalation[0].steps[1].providers",
+		},
+		{
+			name: "inhibition rule without targets",
+			config: `
+refresh = 5
+cooldown = 60
+
+[[alerts.inhibit]]
+source = "DISK:/"
+source_levels = ["CRITICAL"]
+`,
+			expectError: true,
+			errorField:  "alerts.inhibit[0].targets",
+		},
+		{
+			name: "rule with invalid expression",
+			config: `
+refresh = 5
+cooldown = 60
+
+[[rule]]
+name = "cpu-saturated"
+expr = "CPU > 90 &&"
+`,
+			expectError: true,
+			errorField:  "rule[0].expr",
+		},
+		{
+			name: "trend with invalid direction",
+			config: `
+refresh = 5
+cooldown = 60
+
+[[trend]]
+component = "MEMORY"
+window = 300
+direction = "sideways"
+warning = 20
+`,
+			expectError: true,
+			errorField:  "trend[0].direction",
+		},
+		{
+			name: "anomaly critical below warning",
+			config: `
+refresh = 5
+cooldown = 60
+
+[[anomaly]]
+component = "CPU"
+warning = 4
+critical = 3
+`,
+			expectError: true,
+			errorField:  "anomaly[0].critical",
+		},
+		{
+			name: "quiet hours with invalid start time",
+			config: `
+refresh = 5
+cooldown = 60
+
+[alerts.ntfy]
+enabled = true
+topic_url = "https://ntfy.sh/test"
+
+[[alerts.ntfy.quiet_hours]]
+start = "25:00"
+end = "07:00"
+`,
+			expectError: true,
+			errorField:  "alerts.ntfy.quiet_hours[0].start",
+		},
 	}
 
 	for _, tt := range tests {
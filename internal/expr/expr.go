@@ -0,0 +1,431 @@
+// Package expr implements the small expression language of composite alert
+// rules, e.g. `CPU > 90 && LOAD5 > cores` or `"DISK:/var".value >= 95`.
+//
+// Operands are numbers, constants (cores) and component references:
+// COMPONENT or "COMPONENT" (quoted when the name contains other characters
+// than letters, digits, "_" and ":"), optionally followed by a field: .value
+// (the default), .warning, .critical or .level (0 = OK, 1 = WARNING,
+// 2 = CRITICAL). Operators, by increasing precedence: || (or), && (and),
+// ! (not), comparisons (> >= < <= == !=), + -, * /, unary -.
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Fields lists the fields a component reference can read
+var Fields = []string{"value", "warning", "critical", "level"}
+
+// Lookup returns a field of the latest result of a component, false if the
+// component has not reported it
+type Lookup func(component, field string) (float64, bool)
+
+// MissingError is returned when a referenced value is not available
+type MissingError struct {
+	Component string
+	Field     string
+}
+
+func (e *MissingError) Error() string {
+	return fmt.Sprintf("no %s for %s", e.Field, e.Component)
+}
+
+// Expr is a parsed expression
+type Expr struct {
+	source string
+	root   node
+}
+
+// Parse parses an expression
+func Parse(source string) (*Expr, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+	return &Expr{source: source, root: root}, nil
+}
+
+// String returns the source of the expression
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Eval evaluates the expression; any non-zero result is true
+func (e *Expr) Eval(lookup Lookup) (bool, error) {
+	v, err := e.root.eval(lookup)
+	if err != nil {
+		return false, err
+	}
+	return v != 0, nil
+}
+
+// Constants usable in expressions
+var constants = map[string]float64{
+	"cores": float64(runtime.NumCPU()),
+}
+
+type node interface {
+	eval(lookup Lookup) (float64, error)
+}
+
+type numberNode float64
+
+func (n numberNode) eval(Lookup) (float64, error) {
+	return float64(n), nil
+}
+
+type refNode struct {
+	component string
+	field     string
+}
+
+func (n refNode) eval(lookup Lookup) (float64, error) {
+	v, ok := lookup(n.component, n.field)
+	if !ok {
+		return 0, &MissingError{Component: n.component, Field: n.field}
+	}
+	return v, nil
+}
+
+type unaryNode struct {
+	op      string
+	operand node
+}
+
+func (n unaryNode) eval(lookup Lookup) (float64, error) {
+	v, err := n.operand.eval(lookup)
+	if err != nil {
+		return 0, err
+	}
+	if n.op == "-" {
+		return -v, nil
+	}
+	return boolean(v == 0), nil
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n binaryNode) eval(lookup Lookup) (float64, error) {
+	l, err := n.left.eval(lookup)
+	if err != nil {
+		return 0, err
+	}
+
+	// Short-circuit: a missing value on the other side does not matter
+	switch n.op {
+	case "&&":
+		if l == 0 {
+			return 0, nil
+		}
+	case "||":
+		if l != 0 {
+			return 1, nil
+		}
+	}
+
+	r, err := n.right.eval(lookup)
+	if err != nil {
+		return 0, err
+	}
+
+	switch n.op {
+	case "&&", "||":
+		return boolean(r != 0), nil
+	case ">":
+		return boolean(l > r), nil
+	case ">=":
+		return boolean(l >= r), nil
+	case "<":
+		return boolean(l < r), nil
+	case "<=":
+		return boolean(l <= r), nil
+	case "==":
+		return boolean(l == r), nil
+	case "!=":
+		return boolean(l != r), nil
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		if r == 0 {
+			return 0, errors.New("division by zero")
+		}
+		return l / r, nil
+	}
+	return 0, fmt.Errorf("unknown operator %q", n.op)
+}
+
+func boolean(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
+
+// Parser
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token if it is one of the operators
+func (p *parser) accept(ops ...string) (string, bool) {
+	tok := p.peek()
+	if tok.kind != tokOp {
+		return "", false
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) parseOr() (node, error) {
+	return p.parseBinary(p.parseAnd, "||")
+}
+
+func (p *parser) parseAnd() (node, error) {
+	return p.parseBinary(p.parseNot, "&&")
+}
+
+func (p *parser) parseNot() (node, error) {
+	if _, ok := p.accept("!"); ok {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{op: "!", operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if op, ok := p.accept(">", ">=", "<", "<=", "==", "!="); ok {
+		right, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		return binaryNode{op: op, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parseSum() (node, error) {
+	return p.parseBinary(p.parseTerm, "+", "-")
+}
+
+func (p *parser) parseTerm() (node, error) {
+	return p.parseBinary(p.parseUnary, "*", "/")
+}
+
+func (p *parser) parseBinary(operand func() (node, error), ops ...string) (node, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept(ops...)
+		if !ok {
+			return left, nil
+		}
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if _, ok := p.accept("-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{op: "-", operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokNumber:
+		v, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", tok.text, tok.pos)
+		}
+		return numberNode(v), nil
+
+	case tokIdent, tokString:
+		if tok.kind == tokIdent {
+			if v, ok := constants[tok.text]; ok {
+				return numberNode(v), nil
+			}
+		}
+		ref := refNode{component: tok.text, field: "value"}
+		if _, ok := p.accept("."); ok {
+			field := p.next()
+			if field.kind != tokIdent || !contains(Fields, field.text) {
+				return nil, fmt.Errorf("unknown field %q at position %d (expected %s)", field.text, field.pos, strings.Join(Fields, ", "))
+			}
+			ref.field = field.text
+		}
+		return ref, nil
+
+	case tokOp:
+		if tok.text == "(" {
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := p.accept(")"); !ok {
+				return nil, fmt.Errorf("missing ) at position %d", p.peek().pos)
+			}
+			return inner, nil
+		}
+	case tokEOF:
+		return nil, errors.New("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+}
+
+// Tokenizer
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokString
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+var operators = map[string]bool{
+	">": true, ">=": true, "<": true, "<=": true, "==": true, "!=": true,
+	"&&": true, "||": true, "!": true,
+	"+": true, "-": true, "*": true, "/": true,
+	"(": true, ")": true, ".": true,
+}
+
+// Word operators, normalized to their symbol
+var wordOps = map[string]string{"and": "&&", "or": "||", "not": "!"}
+
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	runes := []rune(source)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{tokNumber, string(runes[start:i]), start})
+
+		case isIdentRune(r):
+			start := i
+			for i < len(runes) && isIdentRune(runes[i]) {
+				i++
+			}
+			text := string(runes[start:i])
+			if op, ok := wordOps[text]; ok {
+				tokens = append(tokens, token{tokOp, op, start})
+			} else {
+				tokens = append(tokens, token{tokIdent, text, start})
+			}
+
+		case r == '"':
+			start := i
+			i++
+			for i < len(runes) && runes[i] != '"' {
+				i++
+			}
+			if i == len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", start)
+			}
+			tokens = append(tokens, token{tokString, string(runes[start+1 : i]), start})
+			i++
+
+		default:
+			op := string(r)
+			if i+1 < len(runes) {
+				switch two := string(runes[i : i+2]); two {
+				case ">=", "<=", "==", "!=", "&&", "||":
+					op = two
+				}
+			}
+			if !operators[op] {
+				return nil, fmt.Errorf("unexpected %q at position %d", op, i)
+			}
+			tokens = append(tokens, token{tokOp, op, i})
+			i += len([]rune(op))
+		}
+	}
+
+	return append(tokens, token{tokEOF, "", len(runes)}), nil
+}
+
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == ':'
+}
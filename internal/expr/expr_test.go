@@ -0,0 +1,79 @@
+package expr
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEval(t *testing.T) {
+	values := map[string]map[string]float64{
+		"CPU":       {"value": 95, "warning": 70, "critical": 90, "level": 2},
+		"LOAD5":     {"value": 3.5, "level": 0},
+		"DISK:/var": {"value": 96, "level": 2},
+		"I/O":       {"value": 120},
+	}
+	lookup := func(component, field string) (float64, bool) {
+		v, ok := values[component][field]
+		return v, ok
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"CPU > 90", true},
+		{"CPU.value > 90 && LOAD5 > 4", false},
+		{"CPU > 90 and LOAD5 > 3", true},
+		{"CPU > CPU.critical", true},
+		{"CPU.level == 2 || MISSING > 1", true},
+		{`"DISK:/var".value >= 95`, true},
+		{`"I/O" / 2 == 60`, true},
+		{"!(LOAD5 > 3)", false},
+		{"not LOAD5.level", true},
+		{"-LOAD5 < -3 * 1", true},
+		{"(CPU - 5) * 2 >= 180", true},
+		{"LOAD5 > 0.5 * 2 + 1", true},
+		{".5 < 1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			e, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.expr, err)
+			}
+			got, err := e.Eval(lookup)
+			if err != nil {
+				t.Fatalf("Eval(%q): %v", tt.expr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Eval(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+
+	e, _ := Parse("MISSING.level > 0 && CPU > 90")
+	var missing *MissingError
+	if _, err := e.Eval(lookup); !errors.As(err, &missing) || missing.Component != "MISSING" {
+		t.Errorf("expected a missing value error, got %v", err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, source := range []string{
+		"",
+		"CPU >",
+		"CPU > 90 &&",
+		"(CPU > 90",
+		"CPU > 90)",
+		"CPU.foo > 1",
+		`"DISK:/ > 1`,
+		"CPU = 90",
+		"CPU > 90 & LOAD5 > 1",
+		"CPU # 1",
+	} {
+		if _, err := Parse(source); err == nil {
+			t.Errorf("Parse(%q): expected an error", source)
+		}
+	}
+}
@@ -0,0 +1,393 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Gu1llaum-3/tinymonitor/internal/config"
+	"github.com/Gu1llaum-3/tinymonitor/internal/models"
+)
+
+// cgroupUnlimited is the threshold above which a cgroup v1 memory limit is
+// considered unset (the kernel reports PAGE_COUNTER_MAX rounded to pages)
+const cgroupUnlimited = 1 << 62
+
+// cgroupCounters holds the cumulative cgroup counters used for rates
+type cgroupCounters struct {
+	throttledUsec uint64
+	nrPeriods     uint64
+	nrThrottled   uint64
+	cpuOK         bool
+
+	readBytes  uint64
+	writeBytes uint64
+	ioOK       bool
+
+	time time.Time
+}
+
+// cgroupStats is a snapshot of a cgroup. MemLimit is 0 when unlimited.
+type cgroupStats struct {
+	memUsage uint64
+	memLimit uint64
+	memOK    bool
+	cpuLimit bool
+	counters cgroupCounters
+}
+
+// CgroupCollector monitors resource usage against cgroup limits: memory
+// usage versus its limit, CPU throttling and I/O throughput. It reads cgroup
+// v2 (unified hierarchy) files, falling back to the v1 controllers.
+type CgroupCollector struct {
+	name   string
+	config config.CgroupConfig
+	root   string
+
+	// Replaceable in tests
+	selfCgroupPath string
+	now            func() time.Time
+
+	mu   sync.Mutex
+	last map[string]cgroupCounters
+}
+
+// NewCgroupCollector creates a new cgroup collector
+func NewCgroupCollector(cfg config.CgroupConfig) *CgroupCollector {
+	root := cfg.Root
+	if root == "" {
+		root = "/sys/fs/cgroup"
+	}
+
+	return &CgroupCollector{
+		name:           "cgroup",
+		config:         cfg,
+		root:           root,
+		selfCgroupPath: "/proc/self/cgroup",
+		now:            time.Now,
+		last:           make(map[string]cgroupCounters),
+	}
+}
+
+// Name returns the collector name
+func (c *CgroupCollector) Name() string {
+	return c.name
+}
+
+// Duration returns the configured duration threshold
+func (c *CgroupCollector) Duration() int {
+	return c.config.Duration
+}
+
+// Check executes the cgroup check for each configured path, or for the
+// cgroup TinyMonitor runs in
+func (c *CgroupCollector) Check() []models.MetricResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v2 := fileExists(filepath.Join(c.root, "cgroup.controllers"))
+
+	paths := c.config.Paths
+	if len(paths) == 0 {
+		paths = []string{""}
+	}
+
+	var results []models.MetricResult
+	for _, path := range paths {
+		label, stats, err := c.read(path, v2)
+		if err != nil {
+			sev := models.SeverityWarning
+			results = append(results, models.NewMetricResult("CGROUP_MEMORY:"+label, &sev, "cgroup not found"))
+			continue
+		}
+		results = append(results, c.evaluate(label, stats)...)
+	}
+
+	return results
+}
+
+// evaluate turns a snapshot into results. CPU and I/O are rates, reported
+// from the second check on.
+func (c *CgroupCollector) evaluate(label string, stats cgroupStats) []models.MetricResult {
+	var results []models.MetricResult
+
+	if stats.memOK {
+		if stats.memLimit == 0 {
+			results = append(results, models.NewMetricResult("CGROUP_MEMORY:"+label, nil,
+				formatSize(float64(stats.memUsage))+" (no limit)"))
+		} else {
+			percent := float64(stats.memUsage) / float64(stats.memLimit) * 100
+			results = append(results, models.NewMetricResult("CGROUP_MEMORY:"+label,
+				percentLevel(percent, c.config.Memory),
+				fmt.Sprintf("%s / %s (%.1f%%)", formatSize(float64(stats.memUsage)), formatSize(float64(stats.memLimit)), percent)).
+				WithReading(percent, c.config.Memory.Warning, c.config.Memory.Critical))
+		}
+	}
+
+	current := stats.counters
+	last, ok := c.last[label]
+	c.last[label] = current
+	if !ok {
+		return results
+	}
+
+	seconds := current.time.Sub(last.time).Seconds()
+	if seconds <= 0 {
+		return results
+	}
+
+	if current.cpuOK && last.cpuOK {
+		if !stats.cpuLimit {
+			results = append(results, models.NewMetricResult("CGROUP_CPU:"+label, nil, "no CPU limit"))
+		} else {
+			// throttled_usec is wall-clock time during which the cgroup could
+			// not run because it had used up its quota
+			throttled := math.Min(counterRate(current.throttledUsec, last.throttledUsec, seconds)/1e6*100, 100)
+			periods := counterDelta(current.nrPeriods, last.nrPeriods)
+			throttledPeriods := counterDelta(current.nrThrottled, last.nrThrottled)
+			results = append(results, models.NewMetricResult("CGROUP_CPU:"+label,
+				percentLevel(throttled, c.config.Throttle),
+				fmt.Sprintf("throttled %.1f%% of time (%d/%d periods)", throttled, throttledPeriods, periods)).
+				WithReading(throttled, c.config.Throttle.Warning, c.config.Throttle.Critical))
+		}
+	}
+
+	if current.ioOK && last.ioOK {
+		read := counterRate(current.readBytes, last.readBytes, seconds)
+		write := counterRate(current.writeBytes, last.writeBytes, seconds)
+		total := read + write
+
+		warning := parseByteThreshold(c.config.IOWarning, nil)
+		critical := parseByteThreshold(c.config.IOCritical, nil)
+		var level *models.Severity
+		if total >= critical {
+			sev := models.SeverityCritical
+			level = &sev
+		} else if total >= warning {
+			sev := models.SeverityWarning
+			level = &sev
+		}
+		results = append(results, models.NewMetricResult("CGROUP_IO:"+label, level,
+			fmt.Sprintf("R: %s | W: %s", formatBytes(read), formatBytes(write))).
+			WithReading(total, warning, critical))
+	}
+
+	return results
+}
+
+// counterDelta returns the increase of a counter, 0 if it was reset
+func counterDelta(current, last uint64) uint64 {
+	if current < last {
+		return 0
+	}
+	return current - last
+}
+
+// percentLevel applies a percentage threshold where a zero level is disabled
+func percentLevel(value float64, t config.PercentThreshold) *models.Severity {
+	if t.Critical > 0 && value >= t.Critical {
+		sev := models.SeverityCritical
+		return &sev
+	}
+	if t.Warning > 0 && value >= t.Warning {
+		sev := models.SeverityWarning
+		return &sev
+	}
+	return nil
+}
+
+// read takes a snapshot of the cgroup at path (relative to the cgroup root,
+// empty for our own cgroup) and returns its display label
+func (c *CgroupCollector) read(path string, v2 bool) (string, cgroupStats, error) {
+	if v2 {
+		if path == "" {
+			path = c.ownPath("")
+		}
+		label := cgroupLabel(path)
+		stats, err := c.readV2(filepath.Join(c.root, path))
+		return label, stats, err
+	}
+
+	memPath, cpuPath, ioPath := path, path, path
+	if path == "" {
+		memPath, cpuPath, ioPath = c.ownPath("memory"), c.ownPath("cpu"), c.ownPath("blkio")
+	}
+	label := cgroupLabel(memPath)
+	stats, err := c.readV1(memPath, cpuPath, ioPath)
+	return label, stats, err
+}
+
+// ownPath returns the cgroup of this process for a v1 controller, or the
+// unified hierarchy path when controller is empty. Lines look like
+// "0::/system.slice/tinymonitor.service" (v2) or "4:memory:/docker/abc" (v1).
+func (c *CgroupCollector) ownPath(controller string) string {
+	data, err := os.ReadFile(c.selfCgroupPath)
+	if err != nil {
+		return "/"
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if controller == "" && parts[0] == "0" && parts[1] == "" {
+			return parts[2]
+		}
+		for _, name := range strings.Split(parts[1], ",") {
+			if controller != "" && name == controller {
+				return parts[2]
+			}
+		}
+	}
+	return "/"
+}
+
+func (c *CgroupCollector) readV2(dir string) (cgroupStats, error) {
+	var stats cgroupStats
+	if _, err := os.Stat(dir); err != nil {
+		return stats, err
+	}
+	stats.counters.time = c.now()
+
+	// Memory, without reclaimable page cache (like "docker stats")
+	if current, err := readUint(filepath.Join(dir, "memory.current")); err == nil {
+		stats.memOK = true
+		stats.memUsage = current
+		if memStat, err := readKeyValues(filepath.Join(dir, "memory.stat")); err == nil && memStat["inactive_file"] < current {
+			stats.memUsage -= memStat["inactive_file"]
+		}
+		if limit, err := readUint(filepath.Join(dir, "memory.max")); err == nil {
+			stats.memLimit = limit
+		}
+	}
+
+	// CPU: "nr_periods", "nr_throttled", "throttled_usec"
+	if cpuStat, err := readKeyValues(filepath.Join(dir, "cpu.stat")); err == nil {
+		stats.counters.cpuOK = true
+		stats.counters.nrPeriods = cpuStat["nr_periods"]
+		stats.counters.nrThrottled = cpuStat["nr_throttled"]
+		stats.counters.throttledUsec = cpuStat["throttled_usec"]
+	}
+	if fields, err := readFields(filepath.Join(dir, "cpu.max")); err == nil && len(fields) > 0 {
+		stats.cpuLimit = fields[0] != "max"
+	}
+
+	// I/O: "8:0 rbytes=1024 wbytes=2048 rios=1 wios=2 dbytes=0 dios=0"
+	if data, err := os.ReadFile(filepath.Join(dir, "io.stat")); err == nil {
+		stats.counters.ioOK = true
+		for _, line := range strings.Split(string(data), "\n") {
+			for _, field := range strings.Fields(line) {
+				key, value, ok := strings.Cut(field, "=")
+				if !ok {
+					continue
+				}
+				n, err := strconv.ParseUint(value, 10, 64)
+				if err != nil {
+					continue
+				}
+				switch key {
+				case "rbytes":
+					stats.counters.readBytes += n
+				case "wbytes":
+					stats.counters.writeBytes += n
+				}
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+func (c *CgroupCollector) readV1(memPath, cpuPath, ioPath string) (cgroupStats, error) {
+	var stats cgroupStats
+	memDir := filepath.Join(c.root, "memory", memPath)
+	if _, err := os.Stat(memDir); err != nil {
+		return stats, err
+	}
+	stats.counters.time = c.now()
+
+	if usage, err := readUint(filepath.Join(memDir, "memory.usage_in_bytes")); err == nil {
+		stats.memOK = true
+		stats.memUsage = usage
+		if memStat, err := readKeyValues(filepath.Join(memDir, "memory.stat")); err == nil && memStat["total_inactive_file"] < usage {
+			stats.memUsage -= memStat["total_inactive_file"]
+		}
+		if limit, err := readUint(filepath.Join(memDir, "memory.limit_in_bytes")); err == nil && limit < cgroupUnlimited {
+			stats.memLimit = limit
+		}
+	}
+
+	// The cpu controller is usually mounted as "cpu,cpuacct" with a "cpu"
+	// symlink; throttled_time is in nanoseconds
+	cpuDir := filepath.Join(c.root, "cpu", cpuPath)
+	if cpuStat, err := readKeyValues(filepath.Join(cpuDir, "cpu.stat")); err == nil {
+		stats.counters.cpuOK = true
+		stats.counters.nrPeriods = cpuStat["nr_periods"]
+		stats.counters.nrThrottled = cpuStat["nr_throttled"]
+		stats.counters.throttledUsec = cpuStat["throttled_time"] / 1000
+	}
+	if fields, err := readFields(filepath.Join(cpuDir, "cpu.cfs_quota_us")); err == nil && len(fields) > 0 {
+		stats.cpuLimit = fields[0] != "-1"
+	}
+
+	// "8:0 Read 1024" / "8:0 Write 2048" / "Total 3072"
+	if data, err := os.ReadFile(filepath.Join(c.root, "blkio", ioPath, "blkio.throttle.io_service_bytes")); err == nil {
+		stats.counters.ioOK = true
+		for _, line := range strings.Split(string(data), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) != 3 {
+				continue
+			}
+			n, err := strconv.ParseUint(fields[2], 10, 64)
+			if err != nil {
+				continue
+			}
+			switch fields[1] {
+			case "Read":
+				stats.counters.readBytes += n
+			case "Write":
+				stats.counters.writeBytes += n
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+// cgroupLabel returns the display name of a cgroup path ("/" for the root)
+func cgroupLabel(path string) string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+// readKeyValues parses "key value" lines (memory.stat, cpu.stat)
+func readKeyValues(path string) (map[string]uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	values := make(map[string]uint64)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if v, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			values[fields[0]] = v
+		}
+	}
+	return values, scanner.Err()
+}
@@ -14,3 +14,9 @@ type Collector interface {
 	// Duration returns the configured duration threshold in seconds
 	Duration() int
 }
+
+// Stopper is implemented by collectors that need to persist state when
+// TinyMonitor stops. Stop is called once, after the last Check.
+type Stopper interface {
+	Stop()
+}
@@ -0,0 +1,311 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Gu1llaum-3/tinymonitor/internal/config"
+	"github.com/Gu1llaum-3/tinymonitor/internal/models"
+)
+
+// engineContainer is an entry of GET /containers/json
+type engineContainer struct {
+	ID    string   `json:"Id"`
+	Names []string `json:"Names"`
+}
+
+// engineInspect is the subset of GET /containers/{id}/json used here
+type engineInspect struct {
+	Name         string `json:"Name"`
+	RestartCount int    `json:"RestartCount"`
+	State        struct {
+		Status     string `json:"Status"`
+		Running    bool   `json:"Running"`
+		Restarting bool   `json:"Restarting"`
+		ExitCode   int    `json:"ExitCode"`
+		Health     *struct {
+			Status        string `json:"Status"`
+			FailingStreak int    `json:"FailingStreak"`
+		} `json:"Health"`
+	} `json:"State"`
+}
+
+// engineStats is the subset of GET /containers/{id}/stats used here
+type engineStats struct {
+	CPUStats struct {
+		CPUUsage struct {
+			TotalUsage uint64 `json:"total_usage"`
+		} `json:"cpu_usage"`
+		SystemUsage uint64 `json:"system_cpu_usage"`
+	} `json:"cpu_stats"`
+	MemoryStats struct {
+		Usage uint64            `json:"usage"`
+		Limit uint64            `json:"limit"`
+		Stats map[string]uint64 `json:"stats"`
+	} `json:"memory_stats"`
+}
+
+// cpuSample is the previous CPU reading of a container, used for rates
+type cpuSample struct {
+	container uint64
+	system    uint64
+}
+
+// ContainersCollector monitors Docker or Podman containers through the
+// Docker-compatible Engine API exposed on a unix socket: state, health
+// check status, restarts and optionally CPU and memory usage.
+type ContainersCollector struct {
+	name   string
+	config config.ContainersConfig
+	client *http.Client
+
+	mu       sync.Mutex
+	restarts map[string]int
+	cpu      map[string]cpuSample
+}
+
+// NewContainersCollector creates a new container collector
+func NewContainersCollector(cfg config.ContainersConfig) *ContainersCollector {
+	socket := cfg.Socket
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		},
+	}
+
+	return &ContainersCollector{
+		name:   "containers",
+		config: cfg,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   time.Duration(cfg.Timeout) * time.Second,
+		},
+		restarts: make(map[string]int),
+		cpu:      make(map[string]cpuSample),
+	}
+}
+
+// Name returns the collector name
+func (c *ContainersCollector) Name() string {
+	return c.name
+}
+
+// Duration returns the configured duration threshold
+func (c *ContainersCollector) Duration() int {
+	return c.config.Duration
+}
+
+// Check executes the container check
+func (c *ContainersCollector) Check() []models.MetricResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	containers, err := c.list()
+	if err != nil {
+		sev := models.SeverityCritical
+		return []models.MetricResult{
+			models.NewMetricResult("CONTAINERS", &sev, fmt.Sprintf("Cannot reach container engine: %v", err)),
+		}
+	}
+
+	results := []models.MetricResult{
+		models.NewMetricResult("CONTAINERS", nil, fmt.Sprintf("%d container(s) watched", len(containers))),
+	}
+
+	found := make(map[string]bool)
+	for _, container := range containers {
+		info, err := c.inspect(container.ID)
+		if err != nil {
+			// Removed between list and inspect
+			continue
+		}
+		name := strings.TrimPrefix(info.Name, "/")
+		found[name] = true
+		results = append(results, c.evaluate(container.ID, name, info)...)
+	}
+
+	// Containers configured by exact name must exist
+	for _, pattern := range c.config.Names {
+		if !strings.ContainsAny(pattern, "*?[") && !found[pattern] {
+			sev := models.SeverityCritical
+			results = append(results, models.NewMetricResult("CONTAINER:"+pattern, &sev, "not found"))
+		}
+	}
+
+	// Forget containers that no longer exist
+	for name := range c.restarts {
+		if !found[name] {
+			delete(c.restarts, name)
+			delete(c.cpu, name)
+		}
+	}
+
+	return results
+}
+
+// evaluate builds the results of a single container. A container that is
+// not running, restarting or unhealthy is CRITICAL; a restart since the
+// previous check is a WARNING for one cycle.
+func (c *ContainersCollector) evaluate(id, name string, info engineInspect) []models.MetricResult {
+	var results []models.MetricResult
+
+	var level *models.Severity
+	value := info.State.Status
+	switch {
+	case info.State.Restarting:
+		sev := models.SeverityCritical
+		level = &sev
+		value = fmt.Sprintf("restarting (exit code %d)", info.State.ExitCode)
+	case !info.State.Running:
+		sev := models.SeverityCritical
+		level = &sev
+		value = fmt.Sprintf("%s (exit code %d)", info.State.Status, info.State.ExitCode)
+	case info.State.Health != nil:
+		value = fmt.Sprintf("running (%s)", info.State.Health.Status)
+		if info.State.Health.Status == "unhealthy" {
+			sev := models.SeverityCritical
+			level = &sev
+			value = fmt.Sprintf("running (unhealthy, %d failed checks)", info.State.Health.FailingStreak)
+		}
+	}
+	results = append(results, models.NewMetricResult("CONTAINER:"+name, level, value))
+
+	previous, seen := c.restarts[name]
+	c.restarts[name] = info.RestartCount
+	if seen {
+		var restartLevel *models.Severity
+		restartValue := fmt.Sprintf("%d restart(s)", info.RestartCount)
+		if info.RestartCount > previous {
+			sev := models.SeverityWarning
+			restartLevel = &sev
+			restartValue = fmt.Sprintf("restarted %d time(s) (%d in total)", info.RestartCount-previous, info.RestartCount)
+		}
+		results = append(results, models.NewMetricResult("CONTAINER_RESTARTS:"+name, restartLevel, restartValue))
+	}
+
+	if info.State.Running && c.wantStats() {
+		results = append(results, c.usage(id, name)...)
+	}
+
+	return results
+}
+
+func (c *ContainersCollector) wantStats() bool {
+	return c.config.CPU != (config.PercentThreshold{}) || c.config.Memory != (config.PercentThreshold{})
+}
+
+// usage reports CPU (percent of the host's CPUs since the previous check) and
+// memory (percent of the container limit, page cache excluded)
+func (c *ContainersCollector) usage(id, name string) []models.MetricResult {
+	var stats engineStats
+	if err := c.get("/containers/"+id+"/stats?stream=false&one-shot=true", &stats); err != nil {
+		return nil
+	}
+
+	var results []models.MetricResult
+
+	if c.config.CPU != (config.PercentThreshold{}) {
+		current := cpuSample{container: stats.CPUStats.CPUUsage.TotalUsage, system: stats.CPUStats.SystemUsage}
+		last, ok := c.cpu[name]
+		c.cpu[name] = current
+		if ok && current.system > last.system && current.container >= last.container {
+			percent := float64(current.container-last.container) / float64(current.system-last.system) * 100
+			results = append(results, models.NewMetricResult("CONTAINER_CPU:"+name,
+				percentLevel(percent, c.config.CPU), fmt.Sprintf("%.1f%%", percent)).
+				WithReading(percent, c.config.CPU.Warning, c.config.CPU.Critical))
+		}
+	}
+
+	if c.config.Memory != (config.PercentThreshold{}) && stats.MemoryStats.Limit > 0 {
+		usage := stats.MemoryStats.Usage
+		// cgroup v2 reports inactive_file, cgroup v1 total_inactive_file
+		cache := stats.MemoryStats.Stats["inactive_file"]
+		if cache == 0 {
+			cache = stats.MemoryStats.Stats["total_inactive_file"]
+		}
+		if cache < usage {
+			usage -= cache
+		}
+		percent := float64(usage) / float64(stats.MemoryStats.Limit) * 100
+		results = append(results, models.NewMetricResult("CONTAINER_MEMORY:"+name,
+			percentLevel(percent, c.config.Memory),
+			fmt.Sprintf("%s / %s (%.1f%%)", formatSize(float64(usage)), formatSize(float64(stats.MemoryStats.Limit)), percent)).
+			WithReading(percent, c.config.Memory.Warning, c.config.Memory.Critical))
+	}
+
+	return results
+}
+
+// list returns the containers matching the configured labels and names,
+// sorted by name. Stopped containers are included.
+func (c *ContainersCollector) list() ([]engineContainer, error) {
+	query := url.Values{"all": {"true"}}
+	if len(c.config.Labels) > 0 {
+		filters, err := json.Marshal(map[string][]string{"label": c.config.Labels})
+		if err != nil {
+			return nil, err
+		}
+		query.Set("filters", string(filters))
+	}
+
+	var all []engineContainer
+	if err := c.get("/containers/json?"+query.Encode(), &all); err != nil {
+		return nil, err
+	}
+
+	var selected []engineContainer
+	for _, container := range all {
+		if len(container.Names) == 0 {
+			continue
+		}
+		if c.matchName(strings.TrimPrefix(container.Names[0], "/")) {
+			selected = append(selected, container)
+		}
+	}
+
+	sort.Slice(selected, func(i, j int) bool { return selected[i].Names[0] < selected[j].Names[0] })
+	return selected, nil
+}
+
+func (c *ContainersCollector) matchName(name string) bool {
+	if len(c.config.Names) == 0 {
+		return true
+	}
+	for _, pattern := range c.config.Names {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *ContainersCollector) inspect(id string) (engineInspect, error) {
+	var info engineInspect
+	err := c.get("/containers/"+id+"/json", &info)
+	return info, err
+}
+
+// get performs a GET request on the Engine API and decodes the JSON response.
+// The host part of the URL is ignored: requests always go to the socket.
+func (c *ContainersCollector) get(path string, v any) error {
+	resp, err := c.client.Get("http://engine" + path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: HTTP %d", strings.SplitN(path, "?", 2)[0], resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
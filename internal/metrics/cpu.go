@@ -54,6 +54,7 @@ func (c *CPUCollector) Check() []models.MetricResult {
 	}
 
 	return []models.MetricResult{
-		models.NewMetricResult("CPU", level, fmt.Sprintf("%.1f%%", cpuPercent)),
+		models.NewMetricResult("CPU", level, fmt.Sprintf("%.1f%%", cpuPercent)).
+			WithReading(cpuPercent, c.config.Warning, c.config.Critical),
 	}
 }
@@ -1,25 +1,55 @@
 package metrics
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/Gu1llaum-3/tinymonitor/internal/config"
 	"github.com/Gu1llaum-3/tinymonitor/internal/models"
 	"github.com/shirou/gopsutil/v3/disk"
 )
 
-// DiskCollector monitors filesystem usage
+// DiskCollector monitors filesystem usage and mount health
 type DiskCollector struct {
-	name   string
-	config config.FilesystemConfig
+	name    string
+	config  config.FilesystemConfig
+	timeout time.Duration
+
+	// Data sources, replaceable in tests
+	partitions func(all bool) ([]disk.PartitionStat, error)
+	usage      func(path string) (*disk.UsageStat, error)
+	fstabPath  string
+
+	mu       sync.Mutex
+	inflight map[string]bool // disk.Usage calls still blocked on a hung mount
+	seenRW   map[string]bool // mounts observed read-write at least once
+	fstabRW  map[string]bool // mounts /etc/fstab declares read-write
+	readOnly map[string]bool // mounts currently flagged as remounted read-only
 }
 
 // NewDiskCollector creates a new disk/filesystem collector
 func NewDiskCollector(cfg config.FilesystemConfig) *DiskCollector {
+	timeout := time.Duration(cfg.UsageTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
 	return &DiskCollector{
-		name:   "filesystem",
-		config: cfg,
+		name:       "filesystem",
+		config:     cfg,
+		timeout:    timeout,
+		partitions: disk.Partitions,
+		usage:      disk.Usage,
+		fstabPath:  "/etc/fstab",
+		inflight:   make(map[string]bool),
+		seenRW:     make(map[string]bool),
+		readOnly:   make(map[string]bool),
 	}
 }
 
@@ -35,12 +65,37 @@ func (c *DiskCollector) Duration() int {
 
 // Check executes the filesystem check
 func (c *DiskCollector) Check() []models.MetricResult {
-	partitions, err := disk.Partitions(false)
+	// Partitions(false) leaves out nodev filesystems, network ones included:
+	// the full mount table is needed for expected mounts and NFS/CIFS usage
+	all, err := c.partitions(true)
 	if err != nil {
 		return nil
 	}
+	partitions, err := c.partitions(false)
+	if err != nil {
+		return nil
+	}
+
+	if c.config.DetectReadOnly {
+		c.fstabRW = readFstabRW(c.fstabPath)
+	}
 
 	var results []models.MetricResult
+	mounted := make(map[string]disk.PartitionStat, len(all))
+	for _, part := range all {
+		mounted[part.Mountpoint] = part
+	}
+
+	checked := make(map[string]bool, len(partitions))
+	for _, part := range partitions {
+		checked[part.Mountpoint] = true
+	}
+	for _, part := range all {
+		if networkFstypes[part.Fstype] && !checked[part.Mountpoint] {
+			checked[part.Mountpoint] = true
+			partitions = append(partitions, part)
+		}
+	}
 
 	for _, part := range partitions {
 		// Filter out snap loops and squashfs
@@ -70,7 +125,21 @@ func (c *DiskCollector) Check() []models.MetricResult {
 			continue
 		}
 
-		usage, err := disk.Usage(part.Mountpoint)
+		if c.config.DetectReadOnly && !c.isExpected(part.Mountpoint) {
+			if result, ok := c.checkReadOnly(part); ok {
+				results = append(results, result)
+			}
+		}
+
+		componentName := fmt.Sprintf("DISK:%s", part.Mountpoint)
+
+		usage, err := c.usageWithTimeout(part.Mountpoint)
+		if err == errUsageTimeout {
+			sev := models.SeverityCritical
+			results = append(results, models.NewMetricResult(componentName, &sev,
+				fmt.Sprintf("usage check timed out after %s (hung mount?)", c.timeout)))
+			continue
+		}
 		if err != nil {
 			continue
 		}
@@ -86,13 +155,154 @@ func (c *DiskCollector) Check() []models.MetricResult {
 			level = &sev
 		}
 
-		componentName := fmt.Sprintf("DISK:%s", part.Mountpoint)
-		results = append(results, models.NewMetricResult(componentName, level, fmt.Sprintf("%.1f%%", usagePercent)))
+		results = append(results, models.NewMetricResult(componentName, level, fmt.Sprintf("%.1f%%", usagePercent)).
+			WithReading(usagePercent, c.config.Warning, c.config.Critical))
+	}
+
+	results = append(results, c.checkExpectedMounts(mounted)...)
+
+	return results
+}
+
+// checkExpectedMounts reports every expected mount: CRITICAL when it is
+// missing or has been remounted read-only, OK otherwise.
+func (c *DiskCollector) checkExpectedMounts(mounted map[string]disk.PartitionStat) []models.MetricResult {
+	var results []models.MetricResult
+
+	for _, mountpoint := range c.config.ExpectedMounts {
+		mountpoint = filepath.Clean(mountpoint)
+		componentName := fmt.Sprintf("MOUNT:%s", mountpoint)
+
+		part, ok := mounted[mountpoint]
+		if !ok {
+			sev := models.SeverityCritical
+			results = append(results, models.NewMetricResult(componentName, &sev, "not mounted"))
+			continue
+		}
+
+		if c.config.DetectReadOnly {
+			if result, flagged := c.checkReadOnly(part); flagged {
+				results = append(results, result)
+				continue
+			}
+		}
+
+		results = append(results, models.NewMetricResult(componentName, nil,
+			fmt.Sprintf("mounted (%s %s)", part.Fstype, part.Device)))
 	}
 
 	return results
 }
 
+// checkReadOnly detects mounts that switched from read-write to read-only:
+// mounts seen read-write since startup, or declared read-write in /etc/fstab
+// (already remounted read-only when TinyMonitor starts). Other read-only
+// mounts are not reported. It returns false when there is nothing to report
+// for this mount.
+func (c *DiskCollector) checkReadOnly(part disk.PartitionStat) (models.MetricResult, bool) {
+	componentName := fmt.Sprintf("MOUNT:%s", part.Mountpoint)
+
+	if !hasOpt(part.Opts, "ro") {
+		c.seenRW[part.Mountpoint] = true
+		if c.readOnly[part.Mountpoint] {
+			delete(c.readOnly, part.Mountpoint)
+			return models.NewMetricResult(componentName, nil, "read-write"), true
+		}
+		return models.MetricResult{}, false
+	}
+
+	if !c.seenRW[part.Mountpoint] && !c.fstabRW[part.Mountpoint] {
+		return models.MetricResult{}, false
+	}
+
+	c.readOnly[part.Mountpoint] = true
+	sev := models.SeverityCritical
+	return models.NewMetricResult(componentName, &sev, "remounted read-only"), true
+}
+
+func (c *DiskCollector) isExpected(mountpoint string) bool {
+	for _, expected := range c.config.ExpectedMounts {
+		if filepath.Clean(expected) == mountpoint {
+			return true
+		}
+	}
+	return false
+}
+
+// Network filesystems, mounted nodev but checked like disks
+var networkFstypes = map[string]bool{
+	"nfs":            true,
+	"nfs4":           true,
+	"cifs":           true,
+	"smb3":           true,
+	"smbfs":          true,
+	"ceph":           true,
+	"glusterfs":      true,
+	"fuse.glusterfs": true,
+	"fuse.sshfs":     true,
+}
+
+var errUsageTimeout = errors.New("disk usage timed out")
+
+// usageWithTimeout calls disk.Usage without letting a hung mount (e.g. an
+// unreachable NFS server) block the monitoring loop. While a previous call for
+// the same mount is still blocked, no new call is started.
+func (c *DiskCollector) usageWithTimeout(mountpoint string) (*disk.UsageStat, error) {
+	c.mu.Lock()
+	if c.inflight[mountpoint] {
+		c.mu.Unlock()
+		return nil, errUsageTimeout
+	}
+	c.inflight[mountpoint] = true
+	c.mu.Unlock()
+
+	type usageResult struct {
+		usage *disk.UsageStat
+		err   error
+	}
+	done := make(chan usageResult, 1)
+
+	go func() {
+		usage, err := c.usage(mountpoint)
+		c.mu.Lock()
+		delete(c.inflight, mountpoint)
+		c.mu.Unlock()
+		done <- usageResult{usage, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.usage, r.err
+	case <-time.After(c.timeout):
+		return nil, errUsageTimeout
+	}
+}
+
+// readFstabRW returns the mount points an fstab file mounts read-write (no
+// "ro" option). A missing file yields an empty set.
+func readFstabRW(path string) map[string]bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	rw := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || strings.HasPrefix(fields[0], "#") || fields[2] == "swap" {
+			continue
+		}
+		// Spaces and tabs in paths are octal escapes
+		mountpoint := strings.NewReplacer(`\040`, " ", `\011`, "\t").Replace(fields[1])
+		if !hasOpt(strings.Split(fields[3], ","), "ro") {
+			rw[filepath.Clean(mountpoint)] = true
+		}
+	}
+	return rw
+}
+
 func containsOpt(opts []string, target string) bool {
 	for _, opt := range opts {
 		if strings.Contains(opt, target) {
@@ -101,3 +311,14 @@ func containsOpt(opts []string, target string) bool {
 	}
 	return false
 }
+
+// hasOpt reports whether opts contains exactly target ("ro" must not match
+// "errors=remount-ro")
+func hasOpt(opts []string, target string) bool {
+	for _, opt := range opts {
+		if opt == target {
+			return true
+		}
+	}
+	return false
+}
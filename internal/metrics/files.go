@@ -0,0 +1,148 @@
+package metrics
+
+import (
+	"fmt"
+	"io/fs"
+	"math"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Gu1llaum-3/tinymonitor/internal/config"
+	"github.com/Gu1llaum-3/tinymonitor/internal/models"
+)
+
+// fileScan is the result of scanning the files of a check
+type fileScan struct {
+	count  int
+	size   int64
+	newest time.Time
+}
+
+// FilesCollector checks that files keep being produced (age of the newest
+// file) and that directories stay within size and file count limits. Each
+// [[files.check]] entry is reported as FILES:<name>.
+type FilesCollector struct {
+	name   string
+	config config.FilesConfig
+
+	// Replaceable in tests
+	now func() time.Time
+
+	mu       sync.Mutex
+	lastScan time.Time
+	results  []models.MetricResult
+}
+
+// NewFilesCollector creates a new file freshness collector
+func NewFilesCollector(cfg config.FilesConfig) *FilesCollector {
+	return &FilesCollector{
+		name:   "files",
+		config: cfg,
+		now:    time.Now,
+	}
+}
+
+// Name returns the collector name
+func (c *FilesCollector) Name() string {
+	return c.name
+}
+
+// Duration returns the configured duration threshold
+func (c *FilesCollector) Duration() int {
+	return c.config.Duration
+}
+
+// Check scans the configured paths, at most once per interval; in between
+// the previous results are reported
+func (c *FilesCollector) Check() []models.MetricResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	interval := time.Duration(c.config.Interval) * time.Second
+	if c.results != nil && now.Sub(c.lastScan) < interval {
+		return c.results
+	}
+
+	results := make([]models.MetricResult, 0, len(c.config.Checks))
+	for _, check := range c.config.Checks {
+		results = append(results, c.evaluate(check, scanFiles(check.Path), now))
+	}
+
+	c.lastScan = now
+	c.results = results
+	return results
+}
+
+// evaluate compares a scan with the limits of a check. Every exceeded limit
+// is listed in the value.
+func (c *FilesCollector) evaluate(check config.FileCheck, scan fileScan, now time.Time) models.MetricResult {
+	name := check.Name
+	if name == "" {
+		name = check.Path
+	}
+
+	var problems []string
+
+	age := now.Sub(scan.newest)
+	if check.MaxAge > 0 {
+		maxAge := time.Duration(check.MaxAge) * time.Second
+		if scan.count == 0 {
+			problems = append(problems, "no files found")
+		} else if age > maxAge {
+			problems = append(problems, fmt.Sprintf("newest file %s old (max %s)", formatUptime(age), formatUptime(maxAge)))
+		}
+	}
+
+	if maxSize := parseByteThreshold(check.MaxSize, nil); !math.IsInf(maxSize, 1) && float64(scan.size) > maxSize {
+		problems = append(problems, fmt.Sprintf("size %s (max %s)", formatSize(float64(scan.size)), formatSize(maxSize)))
+	}
+
+	if check.MaxFiles > 0 && scan.count > check.MaxFiles {
+		problems = append(problems, fmt.Sprintf("%d files (max %d)", scan.count, check.MaxFiles))
+	}
+
+	if len(problems) > 0 {
+		sev := models.SeverityWarning
+		if check.Level == "critical" {
+			sev = models.SeverityCritical
+		}
+		return models.NewMetricResult("FILES:"+name, &sev, strings.Join(problems, ", "))
+	}
+
+	value := fmt.Sprintf("%d files, %s", scan.count, formatSize(float64(scan.size)))
+	if scan.count > 0 {
+		value += fmt.Sprintf(", newest %s old", formatUptime(age))
+	}
+	return models.NewMetricResult("FILES:"+name, nil, value)
+}
+
+// scanFiles counts the regular files matching a path: the path itself, every
+// match of a glob, and everything below matching directories. Unreadable
+// entries are skipped.
+func scanFiles(pattern string) fileScan {
+	var scan fileScan
+
+	matches, _ := filepath.Glob(pattern)
+	for _, match := range matches {
+		filepath.WalkDir(match, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || !d.Type().IsRegular() {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			scan.count++
+			scan.size += info.Size()
+			if info.ModTime().After(scan.newest) {
+				scan.newest = info.ModTime()
+			}
+			return nil
+		})
+	}
+
+	return scan
+}
@@ -0,0 +1,263 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Gu1llaum-3/tinymonitor/internal/config"
+	"github.com/Gu1llaum-3/tinymonitor/internal/models"
+	"github.com/Gu1llaum-3/tinymonitor/internal/state"
+)
+
+const heartbeatStateFile = "heartbeats.json"
+
+// heartbeatStatus is the persisted state of a heartbeat
+type heartbeatStatus struct {
+	Created     time.Time `json:"created"`
+	LastPing    time.Time `json:"last_ping"`
+	LastStart   time.Time `json:"last_start,omitempty"`
+	LastFail    time.Time `json:"last_fail,omitempty"`
+	FailMessage string    `json:"fail_message,omitempty"`
+}
+
+// HeartbeatCollector receives pings from cron jobs and batch scripts over
+// HTTP (a dead man's switch) and alerts when a job is late or failed:
+//
+//	/ping/<name>        the job succeeded
+//	/ping/<name>/start  the job started
+//	/ping/<name>/fail   the job failed (the request body is kept as message)
+type HeartbeatCollector struct {
+	name     string
+	config   config.HeartbeatListenerConfig
+	checks   []config.HeartbeatConfig
+	stateDir string
+
+	// Replaceable in tests
+	now func() time.Time
+
+	server    *http.Server
+	listenErr error
+
+	mu     sync.Mutex
+	status map[string]*heartbeatStatus
+}
+
+// NewHeartbeatCollector creates a new heartbeat collector and starts its HTTP
+// listener. stateDir is where the last pings are persisted.
+func NewHeartbeatCollector(cfg config.HeartbeatListenerConfig, checks []config.HeartbeatConfig, stateDir string) *HeartbeatCollector {
+	c := newHeartbeatCollector(cfg, checks, stateDir)
+
+	listener, err := net.Listen("tcp", cfg.Listen)
+	if err != nil {
+		slog.Error("Cannot start heartbeat listener", "listen", cfg.Listen, "error", err)
+		c.listenErr = err
+		return c
+	}
+
+	c.server = &http.Server{
+		Handler:           c,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := c.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			slog.Error("Heartbeat listener stopped", "error", err)
+		}
+	}()
+	slog.Info("Heartbeat listener started", "listen", listener.Addr().String())
+
+	return c
+}
+
+// newHeartbeatCollector creates the collector without starting the listener
+func newHeartbeatCollector(cfg config.HeartbeatListenerConfig, checks []config.HeartbeatConfig, stateDir string) *HeartbeatCollector {
+	c := &HeartbeatCollector{
+		name:     "heartbeat",
+		config:   cfg,
+		checks:   checks,
+		stateDir: stateDir,
+		now:      time.Now,
+		status:   make(map[string]*heartbeatStatus),
+	}
+
+	if stateDir != "" {
+		if err := state.Load(stateDir, heartbeatStateFile, &c.status); err != nil {
+			slog.Warn("Cannot load heartbeat state", "error", err)
+		}
+		if c.status == nil {
+			c.status = make(map[string]*heartbeatStatus)
+		}
+	}
+
+	// Removed heartbeats are forgotten
+	known := make(map[string]bool, len(checks))
+	for _, check := range checks {
+		known[check.Name] = true
+		if c.status[check.Name] == nil {
+			c.status[check.Name] = &heartbeatStatus{}
+		}
+	}
+	for name := range c.status {
+		if !known[name] {
+			delete(c.status, name)
+		}
+	}
+
+	return c
+}
+
+// Name returns the collector name
+func (c *HeartbeatCollector) Name() string {
+	return c.name
+}
+
+// Duration returns the configured duration threshold
+func (c *HeartbeatCollector) Duration() int {
+	return c.config.Duration
+}
+
+// Stop shuts the HTTP listener down
+func (c *HeartbeatCollector) Stop() {
+	if c.server == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	c.server.Shutdown(ctx)
+}
+
+// ServeHTTP records a ping
+func (c *HeartbeatCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path, ok := strings.CutPrefix(r.URL.Path, "/ping/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	name, action, _ := strings.Cut(path, "/")
+
+	// Failure details can be sent as the request body, e.g.
+	// curl --data-raw "$(tail -n 5 backup.log)" .../ping/backup/fail
+	var message string
+	if action == "fail" && r.Body != nil {
+		body, _ := io.ReadAll(io.LimitReader(r.Body, 1024))
+		message = truncate(strings.TrimSpace(string(body)), 200)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	status := c.status[name]
+	if status == nil {
+		http.Error(w, "unknown heartbeat", http.StatusNotFound)
+		return
+	}
+
+	now := c.now()
+	switch action {
+	case "":
+		status.LastPing = now
+	case "start":
+		status.LastStart = now
+	case "fail":
+		status.LastFail = now
+		status.FailMessage = message
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	slog.Debug("Heartbeat received", "name", name, "action", action)
+	c.save()
+	fmt.Fprintln(w, "OK")
+}
+
+// Check evaluates every configured heartbeat
+func (c *HeartbeatCollector) Check() []models.MetricResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var results []models.MetricResult
+	if c.listenErr != nil {
+		sev := models.SeverityCritical
+		results = append(results, models.NewMetricResult("HEARTBEAT", &sev,
+			fmt.Sprintf("Listener not running: %v", c.listenErr)))
+	}
+
+	now := c.now()
+	created := false
+	for _, check := range c.checks {
+		// Heartbeats never pinged are measured from the time they were
+		// first checked
+		status := c.status[check.Name]
+		if status.Created.IsZero() {
+			status.Created = now
+			created = true
+		}
+		results = append(results, evaluateHeartbeat(check, status, now))
+	}
+	if created {
+		c.save()
+	}
+
+	return results
+}
+
+// evaluateHeartbeat applies the period and grace time of a heartbeat. A
+// failure reported after the last success is CRITICAL until the next success.
+func evaluateHeartbeat(check config.HeartbeatConfig, status *heartbeatStatus, now time.Time) models.MetricResult {
+	component := "HEARTBEAT:" + check.Name
+	period := time.Duration(check.Period) * time.Second
+	grace := time.Duration(check.Grace) * time.Second
+
+	if status.LastFail.After(status.LastPing) {
+		value := fmt.Sprintf("failed %s ago", formatUptime(now.Sub(status.LastFail)))
+		if status.FailMessage != "" {
+			value += ": " + status.FailMessage
+		}
+		sev := models.SeverityCritical
+		return models.NewMetricResult(component, &sev, value)
+	}
+
+	reference := status.LastPing
+	lastSeen := fmt.Sprintf("last ping %s ago", formatUptime(now.Sub(status.LastPing)))
+	if reference.IsZero() {
+		reference = status.Created
+		lastSeen = "never pinged"
+	}
+	elapsed := now.Sub(reference)
+	expected := fmt.Sprintf("expected every %s", formatUptime(period))
+
+	switch {
+	case elapsed > period+grace:
+		sev := models.SeverityCritical
+		return models.NewMetricResult(component, &sev, fmt.Sprintf("missing: %s (%s)", lastSeen, expected))
+	case elapsed > period:
+		sev := models.SeverityWarning
+		return models.NewMetricResult(component, &sev, fmt.Sprintf("late: %s (%s)", lastSeen, expected))
+	}
+
+	// Started but not finished within the grace time: probably hung
+	if grace > 0 && status.LastStart.After(status.LastPing) && now.Sub(status.LastStart) > grace {
+		sev := models.SeverityWarning
+		return models.NewMetricResult(component, &sev,
+			fmt.Sprintf("running for %s, %s", formatUptime(now.Sub(status.LastStart)), lastSeen))
+	}
+
+	return models.NewMetricResult(component, nil, lastSeen)
+}
+
+func (c *HeartbeatCollector) save() {
+	if c.stateDir == "" {
+		return
+	}
+	if err := state.Save(c.stateDir, heartbeatStateFile, c.status); err != nil {
+		slog.Warn("Cannot save heartbeat state", "error", err)
+	}
+}
@@ -60,6 +60,13 @@ func (c *IOCollector) Duration() int {
 
 // parseThreshold parses a threshold value (number, string with unit, or percentage)
 func (c *IOCollector) parseThreshold(value interface{}, maxValue *float64) float64 {
+	return parseByteThreshold(value, maxValue)
+}
+
+// parseByteThreshold parses a byte rate threshold: a number, a string with a
+// unit ("100M", "1.5GB") or a percentage of maxValue. Unset or invalid values
+// return +Inf, which never triggers.
+func parseByteThreshold(value interface{}, maxValue *float64) float64 {
 	if value == nil {
 		return math.Inf(1)
 	}
@@ -69,14 +76,17 @@ func (c *IOCollector) parseThreshold(value interface{}, maxValue *float64) float
 		return v
 	case int:
 		return float64(v)
+	case int64:
+		// TOML integers decode as int64
+		return float64(v)
 	case string:
-		return c.parseStringThreshold(v, maxValue)
+		return parseStringThreshold(v, maxValue)
 	}
 
 	return math.Inf(1)
 }
 
-func (c *IOCollector) parseStringThreshold(value string, maxValue *float64) float64 {
+func parseStringThreshold(value string, maxValue *float64) float64 {
 	value = strings.TrimSpace(strings.ToUpper(value))
 
 	// Handle percentage
@@ -125,6 +135,11 @@ func (c *IOCollector) parseStringThreshold(value string, maxValue *float64) floa
 }
 
 func formatBytes(size float64) string {
+	return formatSize(size) + "/s"
+}
+
+// formatSize formats a byte count with a binary unit ("1.5GB")
+func formatSize(size float64) string {
 	power := 1024.0
 	n := 0
 	labels := []string{"", "K", "M", "G", "T"}
@@ -134,7 +149,7 @@ func formatBytes(size float64) string {
 		n++
 	}
 
-	return fmt.Sprintf("%.1f%sB/s", size, labels[n])
+	return fmt.Sprintf("%.1f%sB", size, labels[n])
 }
 
 // Check executes the I/O check
@@ -206,6 +221,7 @@ func (c *IOCollector) Check() []models.MetricResult {
 
 	valueStr := fmt.Sprintf("R: %s W: %s", formattedRead, formattedWrite)
 	return []models.MetricResult{
-		models.NewMetricResult("I/O", level, valueStr),
+		models.NewMetricResult("I/O", level, valueStr).
+			WithReading(totalSpeed, warningThreshold, criticalThreshold),
 	}
 }
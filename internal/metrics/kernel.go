@@ -0,0 +1,259 @@
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Gu1llaum-3/tinymonitor/internal/config"
+	"github.com/Gu1llaum-3/tinymonitor/internal/models"
+)
+
+// kmsgReadTimeout bounds the wait for a kernel log record once the log has
+// been drained
+const kmsgReadTimeout = 10 * time.Millisecond
+
+// oomVictimPattern extracts the victim process name from the kernel OOM
+// killer message ("Out of memory: Killed process 1234 (java) ...", also
+// emitted with a "Memory cgroup" prefix for cgroup OOMs).
+var oomVictimPattern = regexp.MustCompile(`[Kk]illed process \d+ \(([^)]+)\)`)
+
+// KernelCollector detects kernel events: OOM kills (from the oom_kill counter
+// in /proc/vmstat) and, optionally, error messages in the kernel log read from
+// /dev/kmsg. Both are event-based: a result is raised in the cycle where new
+// events appear and returns to normal on the next cycle without new events.
+type KernelCollector struct {
+	name       string
+	config     config.KernelConfig
+	vmstatPath string
+	patterns   []*regexp.Regexp
+
+	lastOOM    uint64
+	hasLastOOM bool
+
+	kmsg    *os.File
+	pending []byte
+	victims []string
+	mu      sync.Mutex
+}
+
+// NewKernelCollector creates a new kernel event collector
+func NewKernelCollector(cfg config.KernelConfig) *KernelCollector {
+	c := &KernelCollector{
+		name:       "kernel",
+		config:     cfg,
+		vmstatPath: cfg.VmstatPath,
+	}
+	if c.vmstatPath == "" {
+		c.vmstatPath = "/proc/vmstat"
+	}
+
+	for _, pattern := range cfg.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			slog.Warn("Ignoring invalid kernel pattern", "pattern", pattern, "error", err)
+			continue
+		}
+		c.patterns = append(c.patterns, re)
+	}
+
+	// Establish the OOM baseline so kills that happened before startup are
+	// not reported.
+	if count, err := readOOMKillCount(c.vmstatPath); err == nil {
+		c.lastOOM = count
+		c.hasLastOOM = true
+	}
+
+	if cfg.Kmsg {
+		c.openKmsg()
+	}
+
+	return c
+}
+
+// openKmsg opens the kernel log in non-blocking mode and skips the existing
+// backlog so only messages logged after startup are considered.
+func (c *KernelCollector) openKmsg() {
+	path := c.config.KmsgPath
+	if path == "" {
+		path = "/dev/kmsg"
+	}
+
+	f, err := os.OpenFile(path, os.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		slog.Warn("Cannot read kernel log, kernel error detection disabled", "path", path, "error", err)
+		return
+	}
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		slog.Warn("Cannot seek kernel log", "path", path, "error", err)
+	}
+	c.kmsg = f
+}
+
+// Name returns the collector name
+func (c *KernelCollector) Name() string {
+	return c.name
+}
+
+// Duration returns the configured duration threshold
+func (c *KernelCollector) Duration() int {
+	return c.config.Duration
+}
+
+// Check executes the kernel event check
+func (c *KernelCollector) Check() []models.MetricResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var results []models.MetricResult
+
+	// Kernel log first: it provides the OOM victim names for the OOM result.
+	var kernelErrors []string
+	c.victims = nil
+	if c.kmsg != nil {
+		for _, line := range c.readKmsg() {
+			if m := oomVictimPattern.FindStringSubmatch(line); m != nil {
+				c.victims = append(c.victims, m[1])
+				continue
+			}
+			for _, re := range c.patterns {
+				if re.MatchString(line) {
+					kernelErrors = append(kernelErrors, line)
+					break
+				}
+			}
+		}
+
+		var level *models.Severity
+		value := "No kernel errors"
+		if len(kernelErrors) > 0 {
+			sev := models.SeverityWarning
+			level = &sev
+			value = fmt.Sprintf("%d new kernel error(s): %s", len(kernelErrors), truncate(kernelErrors[len(kernelErrors)-1], 120))
+		}
+		results = append(results, models.NewMetricResult("KERNEL", level, value))
+	}
+
+	if result, ok := c.checkOOM(); ok {
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// checkOOM compares the oom_kill counter with the previous reading
+func (c *KernelCollector) checkOOM() (models.MetricResult, bool) {
+	count, err := readOOMKillCount(c.vmstatPath)
+	if err != nil {
+		// Not Linux, or kernel older than 4.13 (no oom_kill counter)
+		return models.MetricResult{}, false
+	}
+
+	if !c.hasLastOOM || count < c.lastOOM {
+		c.lastOOM = count
+		c.hasLastOOM = true
+		return models.NewMetricResult("OOM", nil, "No OOM kills"), true
+	}
+
+	newKills := count - c.lastOOM
+	c.lastOOM = count
+
+	if newKills == 0 {
+		return models.NewMetricResult("OOM", nil, "No OOM kills"), true
+	}
+
+	value := fmt.Sprintf("%d new OOM kill(s)", newKills)
+	if len(c.victims) > 0 {
+		value += " (victim: " + strings.Join(c.victims, ", ") + ")"
+	}
+
+	sev := models.SeverityCritical
+	return models.NewMetricResult("OOM", &sev, value), true
+}
+
+// readKmsg drains the kernel log and returns the complete messages read since
+// the previous call. Records from /dev/kmsg ("prio,seq,ts,flags;message") are
+// reduced to their message; plain text lines (fixtures) are returned as-is.
+func (c *KernelCollector) readKmsg() []string {
+	// O_NONBLOCK does not make Read return EAGAIN: the runtime poller parks
+	// it until a record arrives. The deadline ends the drain instead (regular
+	// files do not support deadlines, and never block).
+	_ = c.kmsg.SetReadDeadline(time.Now().Add(kmsgReadTimeout))
+
+	buf := make([]byte, 8192)
+	for {
+		n, err := c.kmsg.Read(buf)
+		if n > 0 {
+			c.pending = append(c.pending, buf[:n]...)
+		}
+		if err != nil {
+			// EPIPE: records were overwritten before we read them; the next
+			// read resumes at the oldest available record.
+			if errors.Is(err, syscall.EPIPE) {
+				continue
+			}
+			break
+		}
+	}
+
+	var lines []string
+	for {
+		idx := bytes.IndexByte(c.pending, '\n')
+		if idx < 0 {
+			break
+		}
+		line := string(c.pending[:idx])
+		c.pending = c.pending[idx+1:]
+
+		// Continuation lines carry key/value metadata (" SUBSYSTEM=...")
+		if line == "" || strings.HasPrefix(line, " ") {
+			continue
+		}
+		if header, message, ok := strings.Cut(line, ";"); ok && strings.Count(header, ",") >= 3 {
+			line = message
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// readOOMKillCount reads the oom_kill counter from a vmstat file
+func readOOMKillCount(path string) (uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "oom_kill" {
+			return strconv.ParseUint(fields[1], 10, 64)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	return 0, fmt.Errorf("oom_kill counter not found in %s", path)
+}
+
+// truncate shortens s to at most max characters
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max-3] + "..."
+}
@@ -0,0 +1,237 @@
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Gu1llaum-3/tinymonitor/internal/config"
+	"github.com/Gu1llaum-3/tinymonitor/internal/models"
+)
+
+// LimitsCollector monitors kernel tables that cause outages when exhausted:
+// system-wide file handles (fs.file-max), conntrack entries (nf_conntrack_max)
+// and PIDs (kernel.pid_max), plus optionally the process closest to its own
+// open files limit.
+type LimitsCollector struct {
+	name     string
+	config   config.LimitsConfig
+	procRoot string
+}
+
+// NewLimitsCollector creates a new kernel limits collector
+func NewLimitsCollector(cfg config.LimitsConfig) *LimitsCollector {
+	procRoot := cfg.ProcRoot
+	if procRoot == "" {
+		procRoot = "/proc"
+	}
+
+	return &LimitsCollector{
+		name:     "limits",
+		config:   cfg,
+		procRoot: procRoot,
+	}
+}
+
+// Name returns the collector name
+func (c *LimitsCollector) Name() string {
+	return c.name
+}
+
+// Duration returns the configured duration threshold
+func (c *LimitsCollector) Duration() int {
+	return c.config.Duration
+}
+
+// Check executes the kernel limits check. Tables that cannot be read (not
+// Linux, conntrack module not loaded) are skipped.
+func (c *LimitsCollector) Check() []models.MetricResult {
+	var results []models.MetricResult
+
+	if used, max, err := c.fileHandles(); err == nil {
+		results = append(results, c.result("FILE_HANDLES", "", used, max))
+	}
+
+	if used, max, err := c.conntrack(); err == nil {
+		results = append(results, c.result("CONNTRACK", "", used, max))
+	}
+
+	if used, max, err := c.pids(); err == nil {
+		results = append(results, c.result("PIDS", "", used, max))
+	}
+
+	if c.config.ProcessFDs {
+		if label, used, max, ok := c.topProcessFDs(); ok {
+			results = append(results, c.result("PROCESS_FDS", label+": ", used, max))
+		} else {
+			results = append(results, models.NewMetricResult("PROCESS_FDS", nil, "no readable process"))
+		}
+	}
+
+	return results
+}
+
+func (c *LimitsCollector) result(component, prefix string, used, max uint64) models.MetricResult {
+	percent := 0.0
+	if max > 0 {
+		percent = float64(used) / float64(max) * 100
+	}
+
+	var level *models.Severity
+	if percent >= c.config.Critical {
+		sev := models.SeverityCritical
+		level = &sev
+	} else if percent >= c.config.Warning {
+		sev := models.SeverityWarning
+		level = &sev
+	}
+
+	return models.NewMetricResult(component, level, fmt.Sprintf("%s%d/%d (%.1f%%)", prefix, used, max, percent)).
+		WithReading(percent, c.config.Warning, c.config.Critical)
+}
+
+// fileHandles reads /proc/sys/fs/file-nr: "allocated unused max"
+func (c *LimitsCollector) fileHandles() (used, max uint64, err error) {
+	fields, err := readFields(filepath.Join(c.procRoot, "sys/fs/file-nr"))
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(fields) < 3 {
+		return 0, 0, fmt.Errorf("unexpected file-nr format")
+	}
+
+	allocated, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	unused, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	max, err = strconv.ParseUint(fields[2], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return allocated - unused, max, nil
+}
+
+// conntrack reads the netfilter connection tracking count and maximum
+func (c *LimitsCollector) conntrack() (used, max uint64, err error) {
+	used, err = readUint(filepath.Join(c.procRoot, "sys/net/netfilter/nf_conntrack_count"))
+	if err != nil {
+		return 0, 0, err
+	}
+	max, err = readUint(filepath.Join(c.procRoot, "sys/net/netfilter/nf_conntrack_max"))
+	if err != nil {
+		return 0, 0, err
+	}
+	return used, max, nil
+}
+
+// pids compares the number of tasks (threads included, as they consume PIDs)
+// from /proc/loadavg with kernel.pid_max
+func (c *LimitsCollector) pids() (used, max uint64, err error) {
+	fields, err := readFields(filepath.Join(c.procRoot, "loadavg"))
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(fields) < 4 {
+		return 0, 0, fmt.Errorf("unexpected loadavg format")
+	}
+
+	// Fourth field is "running/total"
+	_, total, ok := strings.Cut(fields[3], "/")
+	if !ok {
+		return 0, 0, fmt.Errorf("unexpected loadavg format")
+	}
+	used, err = strconv.ParseUint(total, 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	max, err = readUint(filepath.Join(c.procRoot, "sys/kernel/pid_max"))
+	if err != nil {
+		return 0, 0, err
+	}
+	return used, max, nil
+}
+
+// topProcessFDs returns the process with the highest open files usage
+// relative to its soft RLIMIT_NOFILE. Processes that cannot be inspected
+// (insufficient permissions, exited meanwhile) are skipped.
+func (c *LimitsCollector) topProcessFDs() (label string, used, max uint64, ok bool) {
+	entries, err := os.ReadDir(c.procRoot)
+	if err != nil {
+		return "", 0, 0, false
+	}
+
+	bestRatio := -1.0
+	for _, entry := range entries {
+		pid := entry.Name()
+		if _, err := strconv.Atoi(pid); err != nil {
+			continue
+		}
+
+		fds, err := os.ReadDir(filepath.Join(c.procRoot, pid, "fd"))
+		if err != nil {
+			continue
+		}
+		limit, err := readOpenFilesLimit(filepath.Join(c.procRoot, pid, "limits"))
+		if err != nil || limit == 0 {
+			continue
+		}
+
+		ratio := float64(len(fds)) / float64(limit)
+		if ratio > bestRatio {
+			bestRatio = ratio
+			comm, _ := os.ReadFile(filepath.Join(c.procRoot, pid, "comm"))
+			label = fmt.Sprintf("%s (pid %s)", strings.TrimSpace(string(comm)), pid)
+			used = uint64(len(fds))
+			max = limit
+			ok = true
+		}
+	}
+
+	return label, used, max, ok
+}
+
+// readOpenFilesLimit returns the soft "Max open files" limit from
+// /proc/<pid>/limits; 0 means unlimited
+func readOpenFilesLimit(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if !strings.HasPrefix(line, "Max open files") {
+			continue
+		}
+		fields := strings.Fields(strings.TrimPrefix(line, "Max open files"))
+		if len(fields) == 0 || fields[0] == "unlimited" {
+			return 0, nil
+		}
+		return strconv.ParseUint(fields[0], 10, 64)
+	}
+
+	return 0, fmt.Errorf("open files limit not found in %s", path)
+}
+
+func readFields(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(data)), nil
+}
+
+func readUint(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+}
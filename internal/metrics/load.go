@@ -78,6 +78,7 @@ func (c *LoadCollector) Check() []models.MetricResult {
 	}
 
 	return []models.MetricResult{
-		models.NewMetricResult(c.component, level, fmt.Sprintf("%.2f", value)),
+		models.NewMetricResult(c.component, level, fmt.Sprintf("%.2f", value)).
+			WithReading(value, c.warning, c.critical),
 	}
 }
@@ -51,6 +51,7 @@ func (c *MemoryCollector) Check() []models.MetricResult {
 	}
 
 	return []models.MetricResult{
-		models.NewMetricResult("MEMORY", level, fmt.Sprintf("%.1f%%", memPercent)),
+		models.NewMetricResult("MEMORY", level, fmt.Sprintf("%.1f%%", memPercent)).
+			WithReading(memPercent, c.config.Warning, c.config.Critical),
 	}
 }
@@ -1,10 +1,24 @@
 package metrics
 
 import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/Gu1llaum-3/tinymonitor/internal/config"
 	"github.com/Gu1llaum-3/tinymonitor/internal/models"
+	"github.com/shirou/gopsutil/v3/disk"
 )
 
 func TestCPUCollector(t *testing.T) {
@@ -87,6 +101,193 @@ func TestDiskCollector(t *testing.T) {
 	}
 }
 
+func TestDiskCollectorMountChecks(t *testing.T) {
+	collector := NewDiskCollector(config.FilesystemConfig{
+		Warning:        80,
+		Critical:       90,
+		Enabled:        true,
+		ExpectedMounts: []string{"/srv/nfs"},
+		DetectReadOnly: true,
+		UsageTimeout:   1,
+	})
+	collector.fstabPath = filepath.Join(t.TempDir(), "missing")
+
+	parts := []disk.PartitionStat{
+		{Device: "/dev/sda1", Mountpoint: "/", Fstype: "ext4", Opts: []string{"rw", "errors=remount-ro"}},
+	}
+	collector.partitions = func(bool) ([]disk.PartitionStat, error) { return parts, nil }
+	collector.usage = func(path string) (*disk.UsageStat, error) {
+		return &disk.UsageStat{Path: path, UsedPercent: 42}, nil
+	}
+
+	byComponent := func() map[string]models.MetricResult {
+		m := map[string]models.MetricResult{}
+		for _, r := range collector.Check() {
+			m[r.Component] = r
+		}
+		return m
+	}
+
+	// NFS share missing, / read-write
+	results := byComponent()
+	if r := results["MOUNT:/srv/nfs"]; r.Level == nil || *r.Level != models.SeverityCritical {
+		t.Errorf("Expected MOUNT:/srv/nfs CRITICAL, got %+v", r)
+	}
+	if _, ok := results["MOUNT:/"]; ok {
+		t.Error("Read-write mount should not report a MOUNT result")
+	}
+
+	// NFS share mounted, / remounted read-only after errors
+	parts = []disk.PartitionStat{
+		{Device: "/dev/sda1", Mountpoint: "/", Fstype: "ext4", Opts: []string{"ro", "errors=remount-ro"}},
+		{Device: "nas:/export", Mountpoint: "/srv/nfs", Fstype: "nfs4", Opts: []string{"rw"}},
+	}
+	results = byComponent()
+	if r := results["MOUNT:/srv/nfs"]; r.Level != nil {
+		t.Errorf("Expected MOUNT:/srv/nfs OK, got %s", *r.Level)
+	}
+	if r := results["MOUNT:/"]; r.Level == nil || *r.Level != models.SeverityCritical {
+		t.Errorf("Expected MOUNT:/ CRITICAL, got %+v", r)
+	}
+
+	// / back to read-write: a single OK result drives the recovery
+	parts[0].Opts = []string{"rw"}
+	results = byComponent()
+	if r, ok := results["MOUNT:/"]; !ok || r.Level != nil {
+		t.Errorf("Expected MOUNT:/ recovery result, got %+v", r)
+	}
+	if _, ok := byComponent()["MOUNT:/"]; ok {
+		t.Error("MOUNT:/ should no longer be reported once recovered")
+	}
+}
+
+func TestDiskCollectorReadOnlyAtStartup(t *testing.T) {
+	collector := NewDiskCollector(config.FilesystemConfig{
+		Warning:        80,
+		Critical:       90,
+		Enabled:        true,
+		DetectReadOnly: true,
+	})
+	collector.fstabPath = filepath.Join(t.TempDir(), "fstab")
+	writeFile(t, collector.fstabPath, `# <file system> <mount point> <type> <options> <dump> <pass>
+UUID=1234 / ext4 errors=remount-ro 0 1
+UUID=5678 /srv/data\040files xfs defaults 0 2
+/dev/sr0 /media/cdrom iso9660 ro,user,noauto 0 0
+UUID=9abc none swap sw 0 0
+`)
+
+	// Already read-only at the first check, e.g. after a restart
+	collector.partitions = func(bool) ([]disk.PartitionStat, error) {
+		return []disk.PartitionStat{
+			{Device: "/dev/sda1", Mountpoint: "/", Fstype: "ext4", Opts: []string{"ro", "relatime", "errors=remount-ro"}},
+			{Device: "/dev/sdb1", Mountpoint: "/srv/data files", Fstype: "xfs", Opts: []string{"ro"}},
+			{Device: "/dev/sr0", Mountpoint: "/media/cdrom", Fstype: "iso9660", Opts: []string{"ro"}},
+			{Device: "/dev/sdc1", Mountpoint: "/mnt/backup", Fstype: "ext4", Opts: []string{"ro"}},
+		}, nil
+	}
+	collector.usage = func(path string) (*disk.UsageStat, error) {
+		return &disk.UsageStat{Path: path, UsedPercent: 42}, nil
+	}
+
+	results := map[string]models.MetricResult{}
+	for _, r := range collector.Check() {
+		results[r.Component] = r
+	}
+
+	for _, component := range []string{"MOUNT:/", "MOUNT:/srv/data files"} {
+		if r := results[component]; r.Level == nil || *r.Level != models.SeverityCritical {
+			t.Errorf("Expected %s CRITICAL, got %+v", component, r)
+		}
+	}
+	// Read-only in fstab, or not in fstab and never seen read-write
+	for _, component := range []string{"MOUNT:/media/cdrom", "MOUNT:/mnt/backup"} {
+		if _, ok := results[component]; ok {
+			t.Errorf("Unexpected %s result", component)
+		}
+	}
+}
+
+func TestDiskCollectorUsageTimeout(t *testing.T) {
+	collector := NewDiskCollector(config.FilesystemConfig{Warning: 80, Critical: 90, Enabled: true})
+	collector.timeout = 50 * time.Millisecond
+
+	release := make(chan struct{})
+	defer close(release)
+
+	collector.partitions = func(bool) ([]disk.PartitionStat, error) {
+		return []disk.PartitionStat{
+			{Device: "nas:/export", Mountpoint: "/srv/nfs", Fstype: "nfs4", Opts: []string{"rw"}},
+		}, nil
+	}
+	var calls atomic.Int32
+	collector.usage = func(string) (*disk.UsageStat, error) {
+		calls.Add(1)
+		<-release
+		return nil, errors.New("released")
+	}
+
+	for i := 0; i < 2; i++ {
+		results := collector.Check()
+		if len(results) != 1 || results[0].Level == nil || *results[0].Level != models.SeverityCritical {
+			t.Fatalf("check %d: expected a CRITICAL timeout result, got %+v", i, results)
+		}
+	}
+
+	// The second check must not pile up another blocked call
+	if n := calls.Load(); n != 1 {
+		t.Errorf("Expected 1 blocked usage call, got %d", n)
+	}
+}
+
+func TestDiskCollectorNetworkMounts(t *testing.T) {
+	collector := NewDiskCollector(config.FilesystemConfig{
+		Warning:        80,
+		Critical:       90,
+		Enabled:        true,
+		ExpectedMounts: []string{"/srv/nfs", "/mnt/share"},
+	})
+
+	// Like disk.Partitions, only the full table has nodev filesystems
+	collector.partitions = func(all bool) ([]disk.PartitionStat, error) {
+		parts := []disk.PartitionStat{
+			{Device: "/dev/sda1", Mountpoint: "/", Fstype: "ext4", Opts: []string{"rw"}},
+		}
+		if all {
+			parts = append(parts,
+				disk.PartitionStat{Device: "proc", Mountpoint: "/proc", Fstype: "proc", Opts: []string{"rw"}},
+				disk.PartitionStat{Device: "tmpfs", Mountpoint: "/run", Fstype: "tmpfs", Opts: []string{"rw"}},
+				disk.PartitionStat{Device: "nas:/export", Mountpoint: "/srv/nfs", Fstype: "nfs4", Opts: []string{"rw"}},
+				disk.PartitionStat{Device: "//nas/share", Mountpoint: "/mnt/share", Fstype: "cifs", Opts: []string{"rw"}},
+			)
+		}
+		return parts, nil
+	}
+	collector.usage = func(path string) (*disk.UsageStat, error) {
+		return &disk.UsageStat{Path: path, UsedPercent: 42}, nil
+	}
+
+	results := map[string]models.MetricResult{}
+	for _, r := range collector.Check() {
+		results[r.Component] = r
+	}
+
+	for _, component := range []string{"MOUNT:/srv/nfs", "MOUNT:/mnt/share"} {
+		if r, ok := results[component]; !ok || r.Level != nil {
+			t.Errorf("Expected %s OK, got %+v", component, r)
+		}
+	}
+	for _, component := range []string{"DISK:/", "DISK:/srv/nfs", "DISK:/mnt/share"} {
+		if _, ok := results[component]; !ok {
+			t.Errorf("Expected a %s usage result", component)
+		}
+	}
+	for _, component := range []string{"DISK:/proc", "DISK:/run"} {
+		if _, ok := results[component]; ok {
+			t.Errorf("Unexpected %s usage result", component)
+		}
+	}
+}
+
 func TestLoadCollector(t *testing.T) {
 	// Test with auto mode: window5 monitored, window15 opt-in
 	cfg := config.LoadConfig{
@@ -242,6 +443,209 @@ func TestRebootCollector(t *testing.T) {
 	}
 }
 
+func TestRebootCollectorSources(t *testing.T) {
+	dir := t.TempDir()
+	write := func(path, content string) {
+		t.Helper()
+		full := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("osrelease", "6.1.0-18-amd64\n")
+	write("modules/6.1.0-9-amd64/modules.dep", "")
+	write("modules/6.1.0-18-amd64/modules.dep", "")
+	write("modules/6.1.0-20-amd64/modules.dep", "")
+	write("modules/6.2.0-1-amd64/extra.ko", "") // leftover without modules.dep
+	write("reboot-required", "*** System restart required ***\n")
+	write("reboot-required.pkgs", "linux-image-6.1.0-20-amd64\nlibc6\nlibc6\n")
+	write("proc/100/comm", "nginx\n")
+	write("proc/100/maps", "7f00-7f10 r-xp 00000000 08:01 1234 /usr/lib/libssl.so.3 (deleted)\n")
+	write("proc/200/comm", "sshd\n")
+	write("proc/200/maps", "7f00-7f10 r-xp 00000000 08:01 1234 /usr/lib/libssl.so.3\n")
+
+	collector := NewRebootCollector(config.RebootConfig{
+		Enabled:         true,
+		KernelCheck:     true,
+		NeedsRestarting: true,
+		DeletedLibs:     true,
+		Interval:        300,
+	})
+	collector.flagFiles = []string{filepath.Join(dir, "reboot-required")}
+	collector.modulesDirs = []string{filepath.Join(dir, "modules")}
+	collector.bootDir = filepath.Join(dir, "boot")
+	collector.osReleasePath = filepath.Join(dir, "osrelease")
+	collector.procRoot = filepath.Join(dir, "proc")
+	collector.lookPath = func(string) (string, error) { return "/usr/bin/needs-restarting", nil }
+	collector.run = func(name string, args ...string) ([]byte, error) {
+		// needs-restarting -r exits with 1 when a reboot is required
+		err := exec.Command("sh", "-c", "exit 1").Run()
+		return []byte("Core libraries or services have been updated since boot-up:\n  * kernel\n  * glibc\n"), err
+	}
+
+	collector.refresh()
+	results := collector.Check()
+	if len(results) != 2 {
+		t.Fatalf("Expected REBOOT and RESTART results, got %d", len(results))
+	}
+
+	reboot := results[0]
+	if !sameLevel(reboot.Level, ptrSeverity(models.SeverityWarning)) {
+		t.Errorf("Expected REBOOT WARNING, got %v", reboot.Level)
+	}
+	for _, want := range []string{
+		"updates installed: linux-image-6.1.0-20-amd64, libc6;",
+		"running kernel 6.1.0-18-amd64, newest installed 6.1.0-20-amd64",
+		"core packages updated: kernel, glibc",
+	} {
+		if !strings.Contains(reboot.Value, want) {
+			t.Errorf("Expected %q in value, got: %s", want, reboot.Value)
+		}
+	}
+
+	restart := results[1]
+	if restart.Component != "RESTART" || restart.Value != "1 process(es) use deleted libraries: nginx" {
+		t.Errorf("Unexpected RESTART result: %s = %s", restart.Component, restart.Value)
+	}
+
+	// Up to date system: same kernel running, no flag file, needs-restarting exits 0
+	write("osrelease", "6.1.0-20-amd64\n")
+	collector.flagFiles = []string{filepath.Join(dir, "missing")}
+	collector.run = func(name string, args ...string) ([]byte, error) { return nil, nil }
+	collector.refresh()
+
+	results = collector.Check()
+	if results[0].Level != nil {
+		t.Errorf("Expected REBOOT OK, got %v: %s", *results[0].Level, results[0].Value)
+	}
+
+	// Arch: the running kernel's modules are removed by the upgrade, /boot
+	// is used when no modules dir is found
+	collector.modulesDirs = []string{filepath.Join(dir, "nonexistent")}
+	write("boot/vmlinuz-6.9.1-arch1-1", "")
+	write("osrelease", "6.8.9-arch1-2\n")
+	results = collector.Check()
+	if !sameLevel(results[0].Level, ptrSeverity(models.SeverityWarning)) {
+		t.Errorf("Expected REBOOT WARNING from /boot, got %v", results[0].Level)
+	}
+}
+
+func TestRebootCollectorKernelFlavors(t *testing.T) {
+	dir := t.TempDir()
+	for _, release := range []string{"6.8.9-arch1-1", "6.6.30-1-lts", "6.1.0-20-rt-amd64", "6.1.0-18-amd64"} {
+		if err := os.MkdirAll(filepath.Join(dir, "modules", release), 0755); err != nil {
+			t.Fatal(err)
+		}
+		writeFile(t, filepath.Join(dir, "modules", release, "modules.dep"), "")
+	}
+
+	collector := NewRebootCollector(config.RebootConfig{Enabled: true, KernelCheck: true})
+	collector.flagFiles = nil
+	collector.modulesDirs = []string{filepath.Join(dir, "modules")}
+	collector.osReleasePath = filepath.Join(dir, "osrelease")
+
+	tests := []struct {
+		running string
+		newest  string
+	}{
+		// The newest kernel of another flavor is not an upgrade
+		{"6.6.30-1-lts", "6.6.30-1-lts"},
+		{"6.1.0-18-amd64", "6.1.0-18-amd64"},
+		{"6.8.9-arch1-1", "6.8.9-arch1-1"},
+		// The same flavor is
+		{"6.6.29-1-lts", "6.6.30-1-lts"},
+		{"6.1.0-17-rt-amd64", "6.1.0-20-rt-amd64"},
+		{"6.7.4-arch2-1", "6.8.9-arch1-1"},
+	}
+
+	for _, tt := range tests {
+		writeFile(t, collector.osReleasePath, tt.running+"\n")
+		if _, newest := collector.kernelVersions(); newest != tt.newest {
+			t.Errorf("running %s: newest installed %q, want %q", tt.running, newest, tt.newest)
+		}
+		results := collector.Check()
+		if reboot := tt.newest != tt.running; (results[0].Level != nil) != reboot {
+			t.Errorf("running %s: unexpected REBOOT result %v: %s", tt.running, results[0].Level, results[0].Value)
+		}
+	}
+}
+
+func TestRebootCollectorBackgroundRefresh(t *testing.T) {
+	collector := NewRebootCollector(config.RebootConfig{
+		Enabled:         true,
+		NeedsRestarting: true,
+		DeletedLibs:     true,
+		Interval:        300,
+	})
+	collector.flagFiles = nil
+	collector.procRoot = t.TempDir()
+	collector.lookPath = func(string) (string, error) { return "/usr/bin/needs-restarting", nil }
+
+	release := make(chan struct{})
+	var calls atomic.Int32
+	collector.run = func(name string, args ...string) ([]byte, error) {
+		calls.Add(1)
+		<-release
+		err := exec.Command("sh", "-c", "exit 1").Run()
+		return []byte("  * kernel\n"), err
+	}
+
+	// A slow needs-restarting does not block the checks
+	for i := 0; i < 2; i++ {
+		results := collector.Check()
+		if len(results) != 1 || results[0].Level != nil {
+			t.Fatalf("check %d: expected only an OK REBOOT result while refreshing, got %+v", i, results)
+		}
+	}
+
+	close(release)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		collector.mu.Lock()
+		done := !collector.refreshing
+		collector.mu.Unlock()
+		if done {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Background refresh did not complete")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("Expected 1 needs-restarting call, got %d", n)
+	}
+
+	results := collector.Check()
+	if len(results) != 2 || !sameLevel(results[0].Level, ptrSeverity(models.SeverityWarning)) {
+		t.Errorf("Expected the cached reboot reason and a RESTART result, got %+v", results)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"6.1.0-18-amd64", "6.1.0-20-amd64", -1},
+		{"5.14.0-362.8.1.el9_3.x86_64", "5.14.0-70.13.1.el9_0.x86_64", 1},
+		{"6.8.9-arch1-2", "6.8.9-arch1-2", 0},
+		{"6.10.0", "6.9.12", 1},
+		{"4.18.0-513.el8", "4.18.0-513.5.1.el8", -1},
+		{"4.18.0-513.5.1.el8", "4.18.0-513.el8", 1},
+	}
+
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
 func TestCollectorInterface(t *testing.T) {
 	// Verify all collectors implement the Collector interface
 	var _ Collector = (*CPUCollector)(nil)
@@ -250,6 +654,20 @@ func TestCollectorInterface(t *testing.T) {
 	var _ Collector = (*LoadCollector)(nil)
 	var _ Collector = (*IOCollector)(nil)
 	var _ Collector = (*RebootCollector)(nil)
+	var _ Collector = (*KernelCollector)(nil)
+	var _ Collector = (*RAIDCollector)(nil)
+	var _ Collector = (*LimitsCollector)(nil)
+	var _ Collector = (*TCPCollector)(nil)
+	var _ Collector = (*TemperatureCollector)(nil)
+	var _ Collector = (*UpdatesCollector)(nil)
+	var _ Collector = (*UptimeCollector)(nil)
+	var _ Stopper = (*UptimeCollector)(nil)
+	var _ Collector = (*CgroupCollector)(nil)
+	var _ Collector = (*ContainersCollector)(nil)
+	var _ Collector = (*FilesCollector)(nil)
+	var _ Collector = (*HeartbeatCollector)(nil)
+	var _ Stopper = (*HeartbeatCollector)(nil)
+	var _ Collector = (*NTPCollector)(nil)
 }
 
 func TestSeverityLevels(t *testing.T) {
@@ -271,3 +689,1067 @@ func TestSeverityLevels(t *testing.T) {
 
 	_ = cfg // Use cfg to avoid unused variable warning
 }
+
+func TestKernelCollector(t *testing.T) {
+	tmpDir := t.TempDir()
+	vmstatPath := filepath.Join(tmpDir, "vmstat")
+	kmsgPath := filepath.Join(tmpDir, "kmsg")
+
+	writeFile(t, vmstatPath, "nr_free_pages 1000\noom_kill 3\n")
+	// Backlog present before startup must be ignored
+	writeFile(t, kmsgPath, "EXT4-fs error (device sda1): old error\n")
+
+	collector := NewKernelCollector(config.KernelConfig{
+		Enabled:    true,
+		VmstatPath: vmstatPath,
+		Kmsg:       true,
+		KmsgPath:   kmsgPath,
+		Patterns:   []string{`EXT4-fs error`, `blocked for more than \d+ seconds`},
+	})
+
+	if collector.Name() != "kernel" {
+		t.Errorf("Expected name 'kernel', got '%s'", collector.Name())
+	}
+
+	// No new events since startup
+	results := collector.Check()
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	for _, r := range results {
+		if r.Level != nil {
+			t.Errorf("%s: expected OK, got %s (%s)", r.Component, *r.Level, r.Value)
+		}
+	}
+
+	// An OOM kill and a hung task are logged
+	writeFile(t, vmstatPath, "nr_free_pages 1000\noom_kill 4\n")
+	appendFile(t, kmsgPath, "6,100,5000,-;Out of memory: Killed process 4242 (java) total-vm:1024kB\n"+
+		" SUBSYSTEM=memory\n"+
+		"3,101,5001,-;INFO: task kworker:12 blocked for more than 120 seconds.\n")
+
+	results = collector.Check()
+	byComponent := map[string]models.MetricResult{}
+	for _, r := range results {
+		byComponent[r.Component] = r
+	}
+
+	oom := byComponent["OOM"]
+	if oom.Level == nil || *oom.Level != models.SeverityCritical {
+		t.Fatalf("Expected OOM CRITICAL, got %+v", oom)
+	}
+	if !strings.Contains(oom.Value, "1 new OOM kill") || !strings.Contains(oom.Value, "java") {
+		t.Errorf("Unexpected OOM value: %s", oom.Value)
+	}
+
+	kernel := byComponent["KERNEL"]
+	if kernel.Level == nil || *kernel.Level != models.SeverityWarning {
+		t.Fatalf("Expected KERNEL WARNING, got %+v", kernel)
+	}
+	if !strings.Contains(kernel.Value, "blocked for more than 120 seconds") {
+		t.Errorf("Unexpected KERNEL value: %s", kernel.Value)
+	}
+
+	// Next check without new events returns to normal
+	for _, r := range collector.Check() {
+		if r.Level != nil {
+			t.Errorf("%s: expected recovery, got %s", r.Component, *r.Level)
+		}
+	}
+}
+
+func TestKernelCollectorKmsgDoesNotBlock(t *testing.T) {
+	// A pipe blocks like /dev/kmsg once drained
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	collector := NewKernelCollector(config.KernelConfig{
+		Enabled:    true,
+		VmstatPath: filepath.Join(t.TempDir(), "missing"),
+		Patterns:   []string{`EXT4-fs error`},
+	})
+	collector.kmsg = r
+
+	check := func() []models.MetricResult {
+		t.Helper()
+		done := make(chan []models.MetricResult, 1)
+		go func() { done <- collector.Check() }()
+		select {
+		case results := <-done:
+			return results
+		case <-time.After(2 * time.Second):
+			t.Fatal("Check blocked on an empty kernel log")
+			return nil
+		}
+	}
+
+	if _, err := w.WriteString("3,1,1000,-;EXT4-fs error (device sda1): bad block\n"); err != nil {
+		t.Fatal(err)
+	}
+	results := check()
+	if len(results) != 1 || !sameLevel(results[0].Level, ptrSeverity(models.SeverityWarning)) {
+		t.Fatalf("Expected a KERNEL WARNING, got %+v", results)
+	}
+	if results = check(); len(results) != 1 || results[0].Level != nil {
+		t.Errorf("Expected KERNEL OK without new messages, got %+v", results)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func appendFile(t *testing.T, path, content string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", path, err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatalf("Failed to append to %s: %v", path, err)
+	}
+}
+
+func TestRAIDCollector(t *testing.T) {
+	mdstat := `Personalities : [raid1] [raid6] [raid5] [raid4]
+md0 : active raid1 sdb1[1] sda1[0]
+      1046528 blocks super 1.2 [2/2] [UU]
+
+md1 : active raid5 sdf1[3] sde1[1] sdd1[0]
+      2093056 blocks super 1.2 level 5, 512k chunk, algorithm 2 [3/2] [UU_]
+      [====>................]  recovery = 21.4% (224512/1046528) finish=3.2min speed=4237K/sec
+
+md2 : active raid1 sdh1[1](F) sdg1[0]
+      1046528 blocks super 1.2 [2/1] [U_]
+
+md3 : active raid1 sdj1[1] sdi1[0]
+      1046528 blocks super 1.2 [2/1] [_U]
+
+md4 : active raid1 sdl1[1] sdk1[0]
+      1046528 blocks super 1.2 [2/2] [UU]
+      [===>.................]  check = 15.0% (157056/1046528) finish=2.1min speed=7000K/sec
+
+md5 : inactive sdm1[0](S)
+      1046528 blocks super 1.2
+
+md6 : active raid1 sdo1[1] sdn1[0]
+      1046528 blocks super 1.2 [2/2] [UU]
+      [=>...................]  resync =  8.0% (83722/1046528) finish=5.0min speed=3200K/sec
+
+unused devices: <none>
+`
+	path := filepath.Join(t.TempDir(), "mdstat")
+	writeFile(t, path, mdstat)
+
+	collector := NewRAIDCollector(config.RAIDConfig{Enabled: true, MdstatPath: path})
+
+	if collector.Name() != "raid" {
+		t.Errorf("Expected name 'raid', got '%s'", collector.Name())
+	}
+
+	results := collector.Check()
+	if len(results) != 7 {
+		t.Fatalf("Expected 7 results, got %d", len(results))
+	}
+
+	tests := []struct {
+		component string
+		level     *models.Severity
+		contains  string
+	}{
+		{"RAID:md0", nil, "raid1 [2/2] [UU]"},
+		{"RAID:md1", ptrSeverity(models.SeverityCritical), "degraded recovery 21.4% ETA 3.2min"},
+		{"RAID:md2", ptrSeverity(models.SeverityCritical), "failed: sdh1"},
+		{"RAID:md3", ptrSeverity(models.SeverityCritical), "degraded"},
+		{"RAID:md4", nil, "check 15.0%"},
+		{"RAID:md5", ptrSeverity(models.SeverityCritical), "inactive"},
+		{"RAID:md6", ptrSeverity(models.SeverityWarning), "resync 8.0% ETA 5.0min"},
+	}
+
+	for i, tt := range tests {
+		r := results[i]
+		if r.Component != tt.component {
+			t.Errorf("result %d: expected component %s, got %s", i, tt.component, r.Component)
+			continue
+		}
+		if !sameLevel(r.Level, tt.level) {
+			t.Errorf("%s: expected level %v, got %v", tt.component, tt.level, r.Level)
+		}
+		if !strings.Contains(r.Value, tt.contains) {
+			t.Errorf("%s: expected value to contain %q, got %q", tt.component, tt.contains, r.Value)
+		}
+	}
+}
+
+func TestLimitsCollector(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"sys/fs/file-nr":                       "9000\t0\t10000\n",
+		"sys/net/netfilter/nf_conntrack_count": "100\n",
+		"sys/net/netfilter/nf_conntrack_max":   "1000\n",
+		"loadavg":                              "0.10 0.20 0.30 2/30000 4242\n",
+		"sys/kernel/pid_max":                   "32768\n",
+		"100/comm":                             "nginx\n",
+		"100/limits":                           "Limit                     Soft Limit           Hard Limit           Units\nMax open files            4                    4096                 files\n",
+		"200/comm":                             "idle\n",
+		"200/limits":                           "Max open files            1024                 4096                 files\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		writeFile(t, path, content)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "100/fd"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		writeFile(t, filepath.Join(root, "100/fd", strconv.Itoa(i)), "")
+	}
+	if err := os.MkdirAll(filepath.Join(root, "200/fd"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	collector := NewLimitsCollector(config.LimitsConfig{
+		Enabled:    true,
+		Warning:    80,
+		Critical:   95,
+		ProcessFDs: true,
+		ProcRoot:   root,
+	})
+
+	if collector.Name() != "limits" {
+		t.Errorf("Expected name 'limits', got '%s'", collector.Name())
+	}
+
+	results := map[string]models.MetricResult{}
+	for _, r := range collector.Check() {
+		results[r.Component] = r
+	}
+
+	tests := []struct {
+		component string
+		level     *models.Severity
+		value     string
+	}{
+		{"FILE_HANDLES", ptrSeverity(models.SeverityWarning), "9000/10000 (90.0%)"},
+		{"CONNTRACK", nil, "100/1000 (10.0%)"},
+		{"PIDS", ptrSeverity(models.SeverityWarning), "30000/32768 (91.6%)"},
+		{"PROCESS_FDS", nil, "nginx (pid 100): 3/4 (75.0%)"},
+	}
+
+	for _, tt := range tests {
+		r, ok := results[tt.component]
+		if !ok {
+			t.Errorf("Missing result for %s", tt.component)
+			continue
+		}
+		if r.Value != tt.value {
+			t.Errorf("%s: expected value %q, got %q", tt.component, tt.value, r.Value)
+		}
+		if !sameLevel(r.Level, tt.level) {
+			t.Errorf("%s: expected level %v, got %v", tt.component, tt.level, r.Level)
+		}
+	}
+}
+
+func TestTCPCollector(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "net"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	header := "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"
+	// Port 0x1F90 = 8080, 0x0050 = 80; states 08 = CLOSE_WAIT, 06 = TIME_WAIT, 0A = LISTEN
+	writeFile(t, filepath.Join(root, "net/tcp"), header+
+		"   0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1\n"+
+		"   1: 0100007F:1F90 0100007F:D431 08 00000000:00000000 00:00000000 00000000     0        0 2\n"+
+		"   2: 0100007F:1F90 0100007F:D432 08 00000000:00000000 00:00000000 00000000     0        0 3\n"+
+		"   3: 0100007F:0050 0100007F:D433 08 00000000:00000000 00:00000000 00000000     0        0 4\n"+
+		"   4: 0100007F:1F90 0100007F:D434 06 00000000:00000000 00:00000000 00000000     0        0 5\n")
+	writeFile(t, filepath.Join(root, "net/tcp6"), header+
+		"   0: 00000000000000000000000001000000:1F90 00000000000000000000000001000000:D435 08 00000000:00000000 00:00000000 00000000     0        0 6\n")
+
+	netstat := func(overflows, drops int) string {
+		return fmt.Sprintf("TcpExt: SyncookiesSent ListenOverflows ListenDrops\nTcpExt: 0 %d %d\n", overflows, drops)
+	}
+	snmp := func(retrans int) string {
+		return fmt.Sprintf("Tcp: RtoAlgorithm MaxConn RetransSegs\nTcp: 1 -1 %d\n", retrans)
+	}
+	writeFile(t, filepath.Join(root, "net/netstat"), netstat(10, 12))
+	writeFile(t, filepath.Join(root, "net/snmp"), snmp(100))
+
+	collector := NewTCPCollector(config.TCPConfig{
+		Enabled:         true,
+		Ports:           []int{8080},
+		ProcRoot:        root,
+		CloseWait:       config.TCPThreshold{Warning: 2, Critical: 5},
+		ListenOverflows: config.TCPThreshold{Warning: 1, Critical: 10},
+		Retransmits:     config.TCPThreshold{Warning: 50},
+	})
+
+	if collector.Name() != "tcp" {
+		t.Errorf("Expected name 'tcp', got '%s'", collector.Name())
+	}
+
+	// Counters grow by 30 overflows and 100 retransmits over 10 seconds
+	writeFile(t, filepath.Join(root, "net/netstat"), netstat(40, 45))
+	writeFile(t, filepath.Join(root, "net/snmp"), snmp(200))
+	collector.lastTime = time.Now().Add(-10 * time.Second)
+
+	results := map[string]models.MetricResult{}
+	for _, r := range collector.Check() {
+		results[r.Component] = r
+	}
+
+	if len(results) != 3 {
+		t.Errorf("Expected 3 results (only configured thresholds), got %d: %v", len(results), results)
+	}
+
+	if r := results["TCP:CLOSE_WAIT"]; !sameLevel(r.Level, ptrSeverity(models.SeverityWarning)) || !strings.HasPrefix(r.Value, "3 sockets") {
+		t.Errorf("Expected TCP:CLOSE_WAIT WARNING with 3 sockets on port 8080, got %+v", r)
+	}
+
+	if r := results["TCP:LISTEN_OVERFLOWS"]; !sameLevel(r.Level, ptrSeverity(models.SeverityWarning)) || !strings.HasPrefix(r.Value, "3.0") {
+		t.Errorf("Expected TCP:LISTEN_OVERFLOWS WARNING at ~3/s, got %+v", r)
+	}
+
+	if r := results["TCP:RETRANSMITS"]; r.Level != nil {
+		t.Errorf("Expected TCP:RETRANSMITS OK at ~10/s, got %+v", r)
+	}
+}
+
+func TestTemperatureCollector(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		// CPU package: own limits max=80 crit=100, overridden by the glob below
+		"class/hwmon/hwmon0/name":        "coretemp\n",
+		"class/hwmon/hwmon0/temp1_input": "85000\n",
+		"class/hwmon/hwmon0/temp1_label": "Package id 0\n",
+		"class/hwmon/hwmon0/temp1_max":   "80000\n",
+		"class/hwmon/hwmon0/temp1_crit":  "100000\n",
+		// NVMe without label: falls back to its own crit (warning = crit - 10)
+		"class/hwmon/hwmon1/name":        "nvme\n",
+		"class/hwmon/hwmon1/temp1_input": "72000\n",
+		"class/hwmon/hwmon1/temp1_crit":  "80000\n",
+		// Thermal zone with a critical trip point
+		"class/thermal/thermal_zone0/type":              "x86_pkg_temp\n",
+		"class/thermal/thermal_zone0/temp":              "50000\n",
+		"class/thermal/thermal_zone0/trip_point_0_type": "critical\n",
+		"class/thermal/thermal_zone0/trip_point_0_temp": "105000\n",
+		// Excluded sensor
+		"class/thermal/thermal_zone1/type": "acpitz\n",
+		"class/thermal/thermal_zone1/temp": "99000\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		writeFile(t, path, content)
+	}
+
+	collector := NewTemperatureCollector(config.TemperatureConfig{
+		Enabled: true,
+		Exclude: []string{"thermal/acpitz"},
+		Sensors: map[string]config.SensorThreshold{
+			"coretemp/*": {Warning: 90, Critical: 95},
+		},
+		SysRoot: root,
+	})
+
+	if collector.Name() != "temperature" {
+		t.Errorf("Expected name 'temperature', got '%s'", collector.Name())
+	}
+
+	results := map[string]models.MetricResult{}
+	for _, r := range collector.Check() {
+		results[r.Component] = r
+	}
+
+	tests := []struct {
+		component string
+		level     *models.Severity
+		value     string
+	}{
+		{"TEMP:coretemp/Package id 0", nil, "85.0°C"},
+		{"TEMP:nvme/temp1", ptrSeverity(models.SeverityWarning), "72.0°C"},
+		{"TEMP:thermal/x86_pkg_temp", nil, "50.0°C"},
+	}
+
+	if len(results) != len(tests) {
+		t.Errorf("Expected %d results, got %d: %v", len(tests), len(results), results)
+	}
+
+	for _, tt := range tests {
+		r, ok := results[tt.component]
+		if !ok {
+			t.Errorf("Missing result for %s", tt.component)
+			continue
+		}
+		if r.Value != tt.value {
+			t.Errorf("%s: expected value %q, got %q", tt.component, tt.value, r.Value)
+		}
+		if !sameLevel(r.Level, tt.level) {
+			t.Errorf("%s: expected level %v, got %v", tt.component, tt.level, r.Level)
+		}
+	}
+}
+
+func TestUpdatesCollector(t *testing.T) {
+	stateDir := t.TempDir()
+	cfg := config.UpdatesConfig{
+		Enabled:        true,
+		Manager:        "auto",
+		Interval:       3600,
+		Warning:        3,
+		SecurityMaxAge: 7,
+	}
+
+	aptOutput := `Reading package lists...
+Inst libssl3 [3.0.11-1] (3.0.13-1 Debian-Security:12/stable-security [amd64])
+Inst curl [7.88.1-10] (7.88.1-10+deb12u5 Debian:12.5/stable [amd64])
+Conf libssl3 (3.0.13-1 Debian-Security:12/stable-security [amd64])
+`
+	newCollector := func() *UpdatesCollector {
+		c := NewUpdatesCollector(cfg, stateDir)
+		c.lookPath = func(file string) (string, error) {
+			if file == "apt-get" {
+				return "/usr/bin/apt-get", nil
+			}
+			return "", errors.New("not found")
+		}
+		c.run = func(name string, args ...string) ([]byte, error) {
+			if name != "apt-get" {
+				t.Errorf("Unexpected command %s", name)
+			}
+			return []byte(aptOutput), nil
+		}
+		return c
+	}
+
+	collector := newCollector()
+	if collector.Name() != "updates" {
+		t.Errorf("Expected name 'updates', got '%s'", collector.Name())
+	}
+
+	collector.refresh()
+	result := collector.evaluate(time.Now())
+	if result.Level != nil {
+		t.Errorf("Fresh security update should not alert yet, got %s", *result.Level)
+	}
+	if result.Value != "2 pending (1 security, oldest 0d)" {
+		t.Errorf("Unexpected value: %s", result.Value)
+	}
+
+	// The first-seen time is persisted: a restarted collector keeps the age
+	restarted := newCollector()
+	restarted.refresh()
+	result = restarted.evaluate(time.Now().Add(8 * 24 * time.Hour))
+	if !sameLevel(result.Level, ptrSeverity(models.SeverityCritical)) {
+		t.Errorf("Security update pending 8 days should be CRITICAL, got %v (%s)", result.Level, result.Value)
+	}
+}
+
+func TestUpdatesCollectorDnf(t *testing.T) {
+	collector := NewUpdatesCollector(config.UpdatesConfig{Manager: "dnf", Interval: 3600, Warning: 2, SecurityMaxAge: 7}, "")
+	collector.run = func(name string, args ...string) ([]byte, error) {
+		if args[1] == "check-update" {
+			return []byte(`
+openssl-libs.x86_64             1:3.0.9-2.fc38            updates
+kernel.x86_64                   6.8.9-100.fc38            updates
+Obsoleting Packages
+grub2-tools.x86_64              1:2.06-100.fc38           updates
+`), nil
+		}
+		return []byte("FEDORA-2024-1a2b3c Important/Sec. openssl-libs-1:3.0.9-2.fc38.x86_64\n"), nil
+	}
+
+	collector.refresh()
+	result := collector.evaluate(time.Now())
+	if result.Value != "2 pending (1 security, oldest 0d)" {
+		t.Errorf("Unexpected value: %s", result.Value)
+	}
+	if !sameLevel(result.Level, ptrSeverity(models.SeverityWarning)) {
+		t.Errorf("Expected WARNING for 2 pending updates, got %v", result.Level)
+	}
+}
+
+func TestUptimeCollector(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	boot := now.Add(-200 * 24 * time.Hour)
+
+	newCollector := func() *UptimeCollector {
+		c := NewUptimeCollector(config.UptimeConfig{Enabled: true, MaxDays: 180}, dir)
+		c.bootTime = func() (time.Time, error) { return boot, nil }
+		c.now = func() time.Time { return now }
+		return c
+	}
+
+	// First start: boot time recorded, no reboot reported
+	collector := newCollector()
+	results := collector.Check()
+	if len(results) != 2 || results[0].Component != "BOOT" || results[0].Level != nil {
+		t.Fatalf("Expected BOOT OK on first start, got %+v", results)
+	}
+	if results[1].Component != "UPTIME" || !sameLevel(results[1].Level, ptrSeverity(models.SeverityWarning)) {
+		t.Errorf("Expected UPTIME WARNING after 200 days, got %+v", results[1])
+	}
+	if results[1].Value != "200d 0h (max 180d)" {
+		t.Errorf("Unexpected UPTIME value: %s", results[1].Value)
+	}
+
+	// Restart within the same boot (small boot time jitter): nothing reported
+	collector.Stop()
+	boot = boot.Add(time.Second)
+	collector = newCollector()
+	if results := collector.Check(); results[0].Level != nil {
+		t.Errorf("Expected BOOT OK after a restart, got %v: %s", *results[0].Level, results[0].Value)
+	}
+
+	// Clean reboot: TinyMonitor was stopped before the new boot
+	collector.Stop()
+	boot = now.Add(time.Minute)
+	now = boot.Add(time.Minute)
+	collector = newCollector()
+	results = collector.Check()
+	if !sameLevel(results[0].Level, ptrSeverity(models.SeverityWarning)) || !strings.Contains(results[0].Value, "clean shutdown") {
+		t.Errorf("Expected clean reboot WARNING, got %+v", results[0])
+	}
+	if results := collector.Check(); results[0].Level != nil {
+		t.Errorf("Expected reboot alert to clear on next check, got %v", *results[0].Level)
+	}
+
+	// Unexpected reboot: no Stop before the new boot
+	boot = now.Add(10 * time.Minute)
+	now = boot.Add(time.Minute)
+	collector = newCollector()
+	results = collector.Check()
+	if !sameLevel(results[0].Level, ptrSeverity(models.SeverityCritical)) || !strings.Contains(results[0].Value, "unexpectedly") {
+		t.Errorf("Expected unexpected reboot CRITICAL, got %+v", results[0])
+	}
+}
+
+func TestCgroupCollectorV2(t *testing.T) {
+	root := t.TempDir()
+	write := func(path, content string) {
+		t.Helper()
+		full := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("cgroup.controllers", "cpu io memory pids\n")
+	write("self", "0::/system.slice/app.service\n")
+	svc := "system.slice/app.service/"
+	write(svc+"memory.current", "943718400\n") // 900MB
+	write(svc+"memory.stat", "anon 838860800\ninactive_file 104857600\n")
+	write(svc+"memory.max", "1073741824\n") // 1GB
+	write(svc+"cpu.max", "50000 100000\n")
+	write(svc+"cpu.stat", "usage_usec 1000\nnr_periods 100\nnr_throttled 10\nthrottled_usec 1000000\n")
+	write(svc+"io.stat", "8:0 rbytes=1000 wbytes=2000 rios=1 wios=2 dbytes=0 dios=0\n")
+
+	collector := NewCgroupCollector(config.CgroupConfig{
+		Memory:    config.PercentThreshold{Warning: 70, Critical: 95},
+		Throttle:  config.PercentThreshold{Warning: 25, Critical: 50},
+		IOWarning: "1M",
+		Root:      root,
+	})
+	collector.selfCgroupPath = filepath.Join(root, "self")
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	collector.now = func() time.Time { return now }
+
+	// First check: memory only, rates need a baseline
+	results := collector.Check()
+	if len(results) != 1 || results[0].Component != "CGROUP_MEMORY:system.slice/app.service" {
+		t.Fatalf("Expected a single memory result, got %+v", results)
+	}
+	if results[0].Value != "800.0MB / 1024.0MB (78.1%)" || !sameLevel(results[0].Level, ptrSeverity(models.SeverityWarning)) {
+		t.Errorf("Unexpected memory result: %v %s", results[0].Level, results[0].Value)
+	}
+
+	// 10s later: 6s throttled, 4MB written
+	now = now.Add(10 * time.Second)
+	write(svc+"cpu.stat", "usage_usec 5000\nnr_periods 200\nnr_throttled 70\nthrottled_usec 7000000\n")
+	write(svc+"io.stat", "8:0 rbytes=1000 wbytes=41945760 rios=1 wios=2 dbytes=0 dios=0\n")
+
+	results = collector.Check()
+	if len(results) != 3 {
+		t.Fatalf("Expected memory, CPU and IO results, got %d", len(results))
+	}
+	if results[1].Value != "throttled 60.0% of time (60/100 periods)" || !sameLevel(results[1].Level, ptrSeverity(models.SeverityCritical)) {
+		t.Errorf("Unexpected CPU result: %v %s", results[1].Level, results[1].Value)
+	}
+	if results[2].Value != "R: 0.0B/s | W: 4.0MB/s" || !sameLevel(results[2].Level, ptrSeverity(models.SeverityWarning)) {
+		t.Errorf("Unexpected IO result: %v %s", results[2].Level, results[2].Value)
+	}
+
+	// Configured path that does not exist
+	collector.config.Paths = []string{"system.slice/missing.service"}
+	results = collector.Check()
+	if len(results) != 1 || results[0].Component != "CGROUP_MEMORY:system.slice/missing.service" || results[0].Level == nil {
+		t.Errorf("Expected a WARNING for a missing cgroup, got %+v", results)
+	}
+}
+
+func TestCgroupCollectorV1(t *testing.T) {
+	root := t.TempDir()
+	write := func(path, content string) {
+		t.Helper()
+		full := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("self", "5:blkio:/docker/abc\n4:memory:/docker/abc\n3:cpu,cpuacct:/docker/abc\n")
+	write("memory/docker/abc/memory.usage_in_bytes", "524288000\n")
+	write("memory/docker/abc/memory.stat", "total_inactive_file 0\n")
+	write("memory/docker/abc/memory.limit_in_bytes", "9223372036854771712\n")
+	write("cpu/docker/abc/cpu.cfs_quota_us", "-1\n")
+	write("cpu/docker/abc/cpu.stat", "nr_periods 0\nnr_throttled 0\nthrottled_time 0\n")
+
+	collector := NewCgroupCollector(config.CgroupConfig{
+		Memory:   config.PercentThreshold{Warning: 80, Critical: 95},
+		Throttle: config.PercentThreshold{Warning: 25, Critical: 50},
+		Root:     root,
+	})
+	collector.selfCgroupPath = filepath.Join(root, "self")
+	now := time.Now()
+	collector.now = func() time.Time { return now }
+
+	collector.Check()
+	now = now.Add(10 * time.Second)
+	results := collector.Check()
+	if len(results) != 2 {
+		t.Fatalf("Expected memory and CPU results, got %+v", results)
+	}
+	if results[0].Component != "CGROUP_MEMORY:docker/abc" || results[0].Value != "500.0MB (no limit)" || results[0].Level != nil {
+		t.Errorf("Unexpected memory result: %+v", results[0])
+	}
+	if results[1].Value != "no CPU limit" || results[1].Level != nil {
+		t.Errorf("Unexpected CPU result: %+v", results[1])
+	}
+}
+
+func TestContainersCollector(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "engine.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix sockets not available: %v", err)
+	}
+
+	var mu sync.Mutex
+	webRestarts := 0
+	var labelFilter string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/containers/json", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		labelFilter = r.URL.Query().Get("filters")
+		mu.Unlock()
+		fmt.Fprint(w, `[
+			{"Id": "aaa", "Names": ["/web"]},
+			{"Id": "bbb", "Names": ["/db"]},
+			{"Id": "ccc", "Names": ["/worker-1"]},
+			{"Id": "ddd", "Names": ["/other"]}
+		]`)
+	})
+	mux.HandleFunc("/containers/aaa/json", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprintf(w, `{"Name": "/web", "RestartCount": %d, "State": {"Status": "running", "Running": true, "Health": {"Status": "healthy"}}}`, webRestarts)
+	})
+	mux.HandleFunc("/containers/bbb/json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"Name": "/db", "State": {"Status": "running", "Running": true, "Health": {"Status": "unhealthy", "FailingStreak": 3}}}`)
+	})
+	mux.HandleFunc("/containers/ccc/json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"Name": "/worker-1", "State": {"Status": "exited", "Running": false, "ExitCode": 137}}`)
+	})
+	mux.HandleFunc("/containers/aaa/stats", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		usage := 1000000 + webRestarts*600000
+		system := 10000000 + webRestarts*1000000
+		fmt.Fprintf(w, `{"cpu_stats": {"cpu_usage": {"total_usage": %d}, "system_cpu_usage": %d},
+			"memory_stats": {"usage": 629145600, "limit": 1073741824, "stats": {"inactive_file": 104857600}}}`, usage, system)
+	})
+
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	defer server.Close()
+
+	collector := NewContainersCollector(config.ContainersConfig{
+		Socket:  socket,
+		Timeout: 5,
+		Names:   []string{"web", "db", "worker-*", "cache"},
+		Labels:  []string{"com.example.monitor=true"},
+		CPU:     config.PercentThreshold{Warning: 50, Critical: 90},
+		Memory:  config.PercentThreshold{Warning: 40, Critical: 90},
+	})
+
+	byComponent := func(results []models.MetricResult) map[string]models.MetricResult {
+		m := make(map[string]models.MetricResult)
+		for _, r := range results {
+			m[r.Component] = r
+		}
+		return m
+	}
+
+	results := byComponent(collector.Check())
+	if !strings.Contains(labelFilter, "com.example.monitor=true") {
+		t.Errorf("Expected label filter in request, got %q", labelFilter)
+	}
+	if _, ok := results["CONTAINER:other"]; ok {
+		t.Error("Container not matching names should be ignored")
+	}
+
+	tests := []struct {
+		component string
+		level     *models.Severity
+		value     string
+	}{
+		{"CONTAINERS", nil, "3 container(s) watched"},
+		{"CONTAINER:web", nil, "running (healthy)"},
+		{"CONTAINER:db", ptrSeverity(models.SeverityCritical), "running (unhealthy, 3 failed checks)"},
+		{"CONTAINER:worker-1", ptrSeverity(models.SeverityCritical), "exited (exit code 137)"},
+		{"CONTAINER:cache", ptrSeverity(models.SeverityCritical), "not found"},
+		{"CONTAINER_MEMORY:web", ptrSeverity(models.SeverityWarning), "500.0MB / 1024.0MB (48.8%)"},
+	}
+	for _, tt := range tests {
+		r, ok := results[tt.component]
+		if !ok {
+			t.Errorf("Missing result %s", tt.component)
+			continue
+		}
+		if !sameLevel(r.Level, tt.level) || r.Value != tt.value {
+			t.Errorf("%s: got %v %q, expected %v %q", tt.component, r.Level, r.Value, tt.level, tt.value)
+		}
+	}
+
+	// Restart between checks: WARNING once, CPU rate from the previous sample
+	mu.Lock()
+	webRestarts = 2
+	mu.Unlock()
+	results = byComponent(collector.Check())
+	if r := results["CONTAINER_RESTARTS:web"]; !sameLevel(r.Level, ptrSeverity(models.SeverityWarning)) || r.Value != "restarted 2 time(s) (2 in total)" {
+		t.Errorf("Unexpected restart result: %v %q", r.Level, r.Value)
+	}
+	if r := results["CONTAINER_CPU:web"]; !sameLevel(r.Level, ptrSeverity(models.SeverityWarning)) || r.Value != "60.0%" {
+		t.Errorf("Unexpected CPU result: %v %q", r.Level, r.Value)
+	}
+
+	results = byComponent(collector.Check())
+	if r := results["CONTAINER_RESTARTS:web"]; r.Level != nil {
+		t.Errorf("Expected restart alert to clear, got %v", *r.Level)
+	}
+
+	// Engine unreachable
+	server.Close()
+	results = byComponent(collector.Check())
+	if r := results["CONTAINERS"]; !sameLevel(r.Level, ptrSeverity(models.SeverityCritical)) {
+		t.Errorf("Expected CRITICAL when the engine is unreachable, got %+v", r)
+	}
+}
+
+func TestFilesCollector(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+
+	write := func(path string, size int, age time.Duration) {
+		t.Helper()
+		full := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(full, now.Add(-age), now.Add(-age)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("backups/db-1.tar.gz", 1024, 50*time.Hour)
+	write("backups/db-2.tar.gz", 2048, 30*time.Hour)
+	write("backups/notes.txt", 10, time.Minute)
+	write("inbox/a/1.csv", 100, time.Hour)
+	write("inbox/a/2.csv", 100, time.Hour)
+	write("inbox/3.csv", 100, 2*time.Hour)
+
+	collector := NewFilesCollector(config.FilesConfig{
+		Interval: 60,
+		Checks: []config.FileCheck{
+			{Name: "backups", Path: filepath.Join(dir, "backups/*.tar.gz"), MaxAge: 26 * 3600, Level: "critical"},
+			{Name: "inbox", Path: filepath.Join(dir, "inbox"), MaxFiles: 2, MaxSize: int64(1000)},
+			{Path: filepath.Join(dir, "missing/*"), MaxAge: 3600},
+			{Name: "small", Path: filepath.Join(dir, "backups"), MaxSize: "1K"},
+		},
+	})
+	collector.now = func() time.Time { return now }
+
+	results := collector.Check()
+	if len(results) != 4 {
+		t.Fatalf("Expected 4 results, got %d", len(results))
+	}
+
+	tests := []struct {
+		component string
+		level     *models.Severity
+		value     string
+	}{
+		{"FILES:backups", ptrSeverity(models.SeverityCritical), "newest file 1d 6h old (max 1d 2h)"},
+		{"FILES:inbox", ptrSeverity(models.SeverityWarning), "3 files (max 2)"},
+		{"FILES:" + filepath.Join(dir, "missing/*"), ptrSeverity(models.SeverityWarning), "no files found"},
+		{"FILES:small", ptrSeverity(models.SeverityWarning), "size 3.0KB (max 1024.0B)"},
+	}
+	for i, tt := range tests {
+		r := results[i]
+		if r.Component != tt.component || !sameLevel(r.Level, tt.level) || r.Value != tt.value {
+			t.Errorf("Got %s %v %q, expected %s %v %q", r.Component, r.Level, r.Value, tt.component, tt.level, tt.value)
+		}
+	}
+
+	// Results are cached until the interval elapses
+	write("backups/db-3.tar.gz", 2048, time.Hour)
+	if results := collector.Check(); results[0].Level == nil {
+		t.Error("Expected cached result before the interval elapsed")
+	}
+
+	now = now.Add(time.Minute)
+	results = collector.Check()
+	if results[0].Level != nil || results[0].Value != "3 files, 5.0KB, newest 1h 1m old" {
+		t.Errorf("Expected fresh backups to be OK, got %v %q", results[0].Level, results[0].Value)
+	}
+}
+
+func TestHeartbeatCollector(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	checks := []config.HeartbeatConfig{
+		{Name: "backup", Period: 3600, Grace: 600},
+		{Name: "etl", Period: 600, Grace: 0},
+	}
+
+	newCollector := func() *HeartbeatCollector {
+		c := newHeartbeatCollector(config.HeartbeatListenerConfig{}, checks, dir)
+		c.now = func() time.Time { return now }
+		return c
+	}
+	collector := newCollector()
+
+	ping := func(path, body string) int {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		collector.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return rec.Code
+	}
+	levels := func() map[string]models.MetricResult {
+		m := make(map[string]models.MetricResult)
+		for _, r := range collector.Check() {
+			m[r.Component] = r
+		}
+		return m
+	}
+
+	// The first check records when the heartbeats started being watched
+	levels()
+	if code := ping("/ping/unknown", ""); code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown heartbeat, got %d", code)
+	}
+	if code := ping("/ping/backup", ""); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+
+	// Within the period
+	now = now.Add(30 * time.Minute)
+	if r := levels()["HEARTBEAT:backup"]; r.Level != nil || r.Value != "last ping 30m ago" {
+		t.Errorf("Expected OK, got %v %q", r.Level, r.Value)
+	}
+
+	// Started and hung past the grace time
+	ping("/ping/backup/start", "")
+	now = now.Add(20 * time.Minute)
+	if r := levels()["HEARTBEAT:backup"]; !sameLevel(r.Level, ptrSeverity(models.SeverityWarning)) || !strings.HasPrefix(r.Value, "running for 20m") {
+		t.Errorf("Expected running WARNING, got %v %q", r.Level, r.Value)
+	}
+
+	// Late, then missing
+	now = now.Add(15 * time.Minute)
+	results := levels()
+	if r := results["HEARTBEAT:backup"]; !sameLevel(r.Level, ptrSeverity(models.SeverityWarning)) || !strings.HasPrefix(r.Value, "late:") {
+		t.Errorf("Expected late WARNING, got %v %q", r.Level, r.Value)
+	}
+	if r := results["HEARTBEAT:etl"]; !sameLevel(r.Level, ptrSeverity(models.SeverityCritical)) || !strings.Contains(r.Value, "never pinged") {
+		t.Errorf("Expected never pinged CRITICAL, got %v %q", r.Level, r.Value)
+	}
+	now = now.Add(10 * time.Minute)
+	if r := levels()["HEARTBEAT:backup"]; !sameLevel(r.Level, ptrSeverity(models.SeverityCritical)) {
+		t.Errorf("Expected missing CRITICAL, got %v %q", r.Level, r.Value)
+	}
+
+	// Failure with message, persisted across restarts
+	ping("/ping/backup", "")
+	now = now.Add(time.Minute)
+	ping("/ping/backup/fail", "disk full\n")
+	collector = newCollector()
+	if r := levels()["HEARTBEAT:backup"]; !sameLevel(r.Level, ptrSeverity(models.SeverityCritical)) || r.Value != "failed 0m ago: disk full" {
+		t.Errorf("Expected failed CRITICAL after restart, got %v %q", r.Level, r.Value)
+	}
+
+	now = now.Add(time.Minute)
+	ping("/ping/backup", "")
+	if r := levels()["HEARTBEAT:backup"]; r.Level != nil {
+		t.Errorf("Expected success to clear the failure, got %v %q", *r.Level, r.Value)
+	}
+}
+
+func TestHeartbeatListener(t *testing.T) {
+	collector := NewHeartbeatCollector(config.HeartbeatListenerConfig{Listen: "127.0.0.1:0"},
+		[]config.HeartbeatConfig{{Name: "job", Period: 60}}, "")
+	defer collector.Stop()
+
+	if results := collector.Check(); len(results) != 1 || results[0].Component != "HEARTBEAT:job" {
+		t.Errorf("Expected a single HEARTBEAT:job result, got %+v", results)
+	}
+
+	// A port already in use is reported instead of failing silently
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer listener.Close()
+
+	busy := NewHeartbeatCollector(config.HeartbeatListenerConfig{Listen: listener.Addr().String()},
+		[]config.HeartbeatConfig{{Name: "job", Period: 60}}, "")
+	results := busy.Check()
+	if len(results) != 2 || results[0].Component != "HEARTBEAT" || !sameLevel(results[0].Level, ptrSeverity(models.SeverityCritical)) {
+		t.Errorf("Expected listener CRITICAL, got %+v", results)
+	}
+}
+
+func TestNTPCollector(t *testing.T) {
+	cfg := config.NTPConfig{Server: "ntp.test", Interval: 300, Timeout: 1, Warning: 100, Critical: 1000}
+	collector := NewNTPCollector(cfg)
+	status := clockStatus{synced: false, maxError: 16 * time.Second}
+	collector.clockStatus = func() (clockStatus, error) { return status, nil }
+	var offset time.Duration
+	var queryErr error
+	collector.query = func(string, time.Duration) (time.Duration, error) { return offset, queryErr }
+
+	check := func() map[string]models.MetricResult {
+		// Run the background query synchronously
+		collector.refresh()
+		m := make(map[string]models.MetricResult)
+		for _, r := range collector.Check() {
+			m[r.Component] = r
+		}
+		return m
+	}
+
+	offset = -250 * time.Millisecond
+	results := check()
+	if r := results["NTP_SYNC"]; !sameLevel(r.Level, ptrSeverity(models.SeverityWarning)) || r.Value != "clock not synchronised (max error 16.0s)" {
+		t.Errorf("Expected unsynchronised WARNING, got %v %q", r.Level, r.Value)
+	}
+	if r := results["NTP_OFFSET"]; !sameLevel(r.Level, ptrSeverity(models.SeverityWarning)) || r.Value != "-250.0ms from ntp.test" {
+		t.Errorf("Expected offset WARNING, got %v %q", r.Level, r.Value)
+	}
+
+	status = clockStatus{synced: true, maxError: 1500 * time.Microsecond}
+	offset = 2 * time.Second
+	results = check()
+	if r := results["NTP_SYNC"]; r.Level != nil || r.Value != "synchronised (max error 1.5ms)" {
+		t.Errorf("Expected synchronised, got %v %q", r.Level, r.Value)
+	}
+	if r := results["NTP_OFFSET"]; !sameLevel(r.Level, ptrSeverity(models.SeverityCritical)) {
+		t.Errorf("Expected offset CRITICAL, got %v %q", r.Level, r.Value)
+	}
+
+	offset = 5 * time.Millisecond
+	if r := check()["NTP_OFFSET"]; r.Level != nil {
+		t.Errorf("Expected offset OK, got %v %q", *r.Level, r.Value)
+	}
+
+	queryErr = errors.New("i/o timeout")
+	if r := check()["NTP_OFFSET"]; !sameLevel(r.Level, ptrSeverity(models.SeverityWarning)) || !strings.Contains(r.Value, "i/o timeout") {
+		t.Errorf("Expected query failure WARNING, got %v %q", r.Level, r.Value)
+	}
+}
+
+func TestQuerySNTP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer conn.Close()
+
+	// Fake server running 3 seconds ahead of the local clock
+	ahead := 3 * time.Second
+	var stratum atomic.Int32
+	stratum.Store(2)
+	go func() {
+		buf := make([]byte, 48)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if n < 48 {
+				continue
+			}
+			resp := make([]byte, 48)
+			resp[0] = 0x24 // version 4, mode 4 (server)
+			resp[1] = byte(stratum.Load())
+			copy(resp[24:32], buf[40:48])
+			now := time.Now().Add(ahead)
+			putNTPTime(resp[32:40], now)
+			putNTPTime(resp[40:48], now)
+			conn.WriteTo(resp, addr)
+		}
+	}()
+
+	offset, err := querySNTP(conn.LocalAddr().String(), time.Second)
+	if err != nil {
+		t.Fatalf("querySNTP failed: %v", err)
+	}
+	if diff := offset - ahead; diff < -100*time.Millisecond || diff > 100*time.Millisecond {
+		t.Errorf("Expected an offset close to %s, got %s", ahead, offset)
+	}
+
+	// Kiss-o'-death: stratum 0
+	stratum.Store(0)
+	if _, err := querySNTP(conn.LocalAddr().String(), time.Second); err == nil {
+		t.Error("Expected an error for a stratum 0 response")
+	}
+}
+
+// ptrSeverity is a helper to create a pointer to a Severity value
+func ptrSeverity(s models.Severity) *models.Severity {
+	return &s
+}
+
+// sameLevel compares two optional severities (nil means OK)
+func sameLevel(a, b *models.Severity) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,214 @@
+package metrics
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/Gu1llaum-3/tinymonitor/internal/config"
+	"github.com/Gu1llaum-3/tinymonitor/internal/models"
+)
+
+// ntpEpochOffset is the number of seconds between the NTP epoch (1900) and
+// the Unix epoch (1970)
+const ntpEpochOffset = 2208988800
+
+// clockStatus is the kernel view of the clock synchronisation
+type clockStatus struct {
+	synced   bool
+	maxError time.Duration
+}
+
+// NTPCollector monitors clock synchronisation: the kernel sync status and,
+// when a server is configured, the clock offset measured with SNTP. Server
+// queries run in the background every Interval seconds.
+type NTPCollector struct {
+	name   string
+	config config.NTPConfig
+
+	// Replaceable in tests
+	clockStatus func() (clockStatus, error)
+	query       func(server string, timeout time.Duration) (time.Duration, error)
+
+	mu         sync.Mutex
+	refreshing bool
+	lastQuery  time.Time
+	offset     time.Duration
+	queryErr   error
+	queried    bool
+}
+
+// NewNTPCollector creates a new NTP collector
+func NewNTPCollector(cfg config.NTPConfig) *NTPCollector {
+	return &NTPCollector{
+		name:        "ntp",
+		config:      cfg,
+		clockStatus: kernelClockStatus,
+		query:       querySNTP,
+	}
+}
+
+// Name returns the collector name
+func (c *NTPCollector) Name() string {
+	return c.name
+}
+
+// Duration returns the configured duration threshold
+func (c *NTPCollector) Duration() int {
+	return c.config.Duration
+}
+
+// Check reports the kernel sync status (NTP_SYNC) and the last measured
+// offset (NTP_OFFSET)
+func (c *NTPCollector) Check() []models.MetricResult {
+	var results []models.MetricResult
+
+	if status, err := c.clockStatus(); err == nil {
+		if status.synced {
+			results = append(results, models.NewMetricResult("NTP_SYNC", nil,
+				fmt.Sprintf("synchronised (max error %s)", formatMillis(status.maxError))))
+		} else {
+			sev := models.SeverityWarning
+			results = append(results, models.NewMetricResult("NTP_SYNC", &sev,
+				fmt.Sprintf("clock not synchronised (max error %s)", formatMillis(status.maxError))))
+		}
+	}
+
+	if c.config.Server == "" {
+		return results
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	interval := time.Duration(c.config.Interval) * time.Second
+	if !c.refreshing && (c.lastQuery.IsZero() || time.Since(c.lastQuery) >= interval) {
+		c.refreshing = true
+		go c.refresh()
+	}
+
+	if !c.queried {
+		return results
+	}
+
+	if c.queryErr != nil {
+		sev := models.SeverityWarning
+		return append(results, models.NewMetricResult("NTP_OFFSET", &sev,
+			fmt.Sprintf("Cannot query %s: %v", c.config.Server, c.queryErr)))
+	}
+
+	offsetMs := math.Abs(float64(c.offset) / float64(time.Millisecond))
+	var level *models.Severity
+	if offsetMs >= c.config.Critical {
+		sev := models.SeverityCritical
+		level = &sev
+	} else if offsetMs >= c.config.Warning {
+		sev := models.SeverityWarning
+		level = &sev
+	}
+
+	return append(results, models.NewMetricResult("NTP_OFFSET", level,
+		fmt.Sprintf("%+.1fms from %s", float64(c.offset)/float64(time.Millisecond), c.config.Server)).
+		WithReading(offsetMs, c.config.Warning, c.config.Critical))
+}
+
+// refresh queries the NTP server and caches the offset
+func (c *NTPCollector) refresh() {
+	offset, err := c.query(c.config.Server, time.Duration(c.config.Timeout)*time.Second)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.refreshing = false
+	c.lastQuery = time.Now()
+	c.queried = true
+	c.offset = offset
+	c.queryErr = err
+
+	if err != nil {
+		slog.Debug("NTP query failed", "server", c.config.Server, "error", err)
+	}
+}
+
+// querySNTP sends a single SNTP (RFC 4330) request and returns the offset of
+// the local clock relative to the server: positive when the local clock is
+// behind. The server is "host" or "host:port" (default port 123).
+func querySNTP(server string, timeout time.Duration) (time.Duration, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "123")
+	}
+
+	conn, err := net.DialTimeout("udp", server, timeout)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	// LI = 0, version 4, mode 3 (client); the transmit timestamp is echoed
+	// back by the server as origin timestamp
+	req := make([]byte, 48)
+	req[0] = 0x23
+	t1 := time.Now()
+	putNTPTime(req[40:], t1)
+
+	if _, err := conn.Write(req); err != nil {
+		return 0, err
+	}
+
+	resp := make([]byte, 48)
+	n, err := conn.Read(resp)
+	t4 := time.Now()
+	if err != nil {
+		return 0, err
+	}
+	if n < 48 {
+		return 0, errors.New("short NTP response")
+	}
+
+	if mode := resp[0] & 0x07; mode != 4 {
+		return 0, fmt.Errorf("unexpected NTP mode %d", mode)
+	}
+	if resp[1] == 0 {
+		return 0, fmt.Errorf("server refused the request (kiss code %q)", string(resp[12:16]))
+	}
+	if resp[0]>>6 == 3 {
+		return 0, errors.New("server clock is not synchronised")
+	}
+	if binary.BigEndian.Uint64(resp[24:32]) != binary.BigEndian.Uint64(req[40:48]) {
+		return 0, errors.New("NTP response does not match the request")
+	}
+
+	t2 := ntpTime(resp[32:40])
+	t3 := ntpTime(resp[40:48])
+
+	return (t2.Sub(t1) + t3.Sub(t4)) / 2, nil
+}
+
+// putNTPTime encodes t as a 64-bit NTP timestamp
+func putNTPTime(b []byte, t time.Time) {
+	seconds := uint64(t.Unix() + ntpEpochOffset)
+	fraction := uint64(t.Nanosecond()) << 32 / 1e9
+	binary.BigEndian.PutUint64(b, seconds<<32|fraction)
+}
+
+// ntpTime decodes a 64-bit NTP timestamp
+func ntpTime(b []byte) time.Time {
+	v := binary.BigEndian.Uint64(b)
+	seconds := int64(v>>32) - ntpEpochOffset
+	nanos := (v & 0xffffffff) * 1e9 >> 32
+	return time.Unix(seconds, int64(nanos))
+}
+
+// formatMillis formats a duration in milliseconds, or seconds above 1s
+func formatMillis(d time.Duration) string {
+	if d >= time.Second {
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	}
+	return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+}
@@ -0,0 +1,25 @@
+//go:build linux
+
+package metrics
+
+import (
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// kernelClockStatus reads the kernel clock discipline with adjtimex (read-only,
+// no privileges needed). The clock is unsynchronised when the STA_UNSYNC flag
+// is set, which happens when no NTP daemon disciplines it.
+func kernelClockStatus() (clockStatus, error) {
+	var tx unix.Timex
+	state, err := unix.Adjtimex(&tx)
+	if err != nil {
+		return clockStatus{}, err
+	}
+
+	return clockStatus{
+		synced:   tx.Status&unix.STA_UNSYNC == 0 && state != unix.TIME_ERROR,
+		maxError: time.Duration(int64(tx.Maxerror)) * time.Microsecond,
+	}, nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package metrics
+
+import "errors"
+
+// kernelClockStatus is only implemented on Linux (adjtimex)
+func kernelClockStatus() (clockStatus, error) {
+	return clockStatus{}, errors.New("not supported on this platform")
+}
@@ -0,0 +1,218 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Gu1llaum-3/tinymonitor/internal/config"
+	"github.com/Gu1llaum-3/tinymonitor/internal/models"
+)
+
+var (
+	// "[2/1] [U_]": configured and active member counts, then member status
+	mdStatusPattern = regexp.MustCompile(`\[(\d+)/(\d+)\]\s+\[([U_]+)\]`)
+	// "recovery =  8.5% (123/456) finish=12.3min speed=..."
+	mdSyncPattern = regexp.MustCompile(`(resync|recovery|reshape|check)\s*=\s*([\d.]+)%.*?finish=(\S+)`)
+	// "resync=DELAYED" / "resync=PENDING"
+	mdSyncPendingPattern = regexp.MustCompile(`(resync|recovery|reshape)=(DELAYED|PENDING)`)
+)
+
+// mdArray is the parsed state of a single md device from /proc/mdstat
+type mdArray struct {
+	Name        string
+	Active      bool
+	Level       string
+	Devices     int
+	ActiveDevs  int
+	Status      string
+	Failed      []string
+	SyncAction  string
+	SyncPercent float64
+	SyncFinish  string
+}
+
+// Degraded returns true if fewer members are active than configured
+func (a mdArray) Degraded() bool {
+	return a.ActiveDevs < a.Devices
+}
+
+// RAIDCollector monitors Linux software RAID arrays
+type RAIDCollector struct {
+	name   string
+	config config.RAIDConfig
+	path   string
+}
+
+// NewRAIDCollector creates a new software RAID collector
+func NewRAIDCollector(cfg config.RAIDConfig) *RAIDCollector {
+	path := cfg.MdstatPath
+	if path == "" {
+		path = "/proc/mdstat"
+	}
+
+	return &RAIDCollector{
+		name:   "raid",
+		config: cfg,
+		path:   path,
+	}
+}
+
+// Name returns the collector name
+func (c *RAIDCollector) Name() string {
+	return c.name
+}
+
+// Duration returns the configured duration threshold
+func (c *RAIDCollector) Duration() int {
+	return c.config.Duration
+}
+
+// Check executes the RAID check, emitting one result per md device
+func (c *RAIDCollector) Check() []models.MetricResult {
+	f, err := os.Open(c.path)
+	if err != nil {
+		// Not Linux, or md driver not loaded
+		return nil
+	}
+	defer f.Close()
+
+	arrays, err := parseMdstat(f)
+	if err != nil {
+		return nil
+	}
+
+	results := make([]models.MetricResult, 0, len(arrays))
+	for _, array := range arrays {
+		level, value := evaluateArray(array)
+		results = append(results, models.NewMetricResult("RAID:"+array.Name, level, value))
+	}
+
+	return results
+}
+
+// evaluateArray returns the severity and formatted value for an array.
+// Failed members, inactive and degraded arrays are CRITICAL, also while a
+// rebuild runs: there is no redundancy until it completes. A resync/reshape
+// of a healthy array is a WARNING.
+func evaluateArray(a mdArray) (*models.Severity, string) {
+	var parts []string
+	if a.Level != "" {
+		parts = append(parts, a.Level)
+	}
+	if a.Devices > 0 {
+		parts = append(parts, fmt.Sprintf("[%d/%d] [%s]", a.Devices, a.ActiveDevs, a.Status))
+	}
+
+	var sev models.Severity
+	switch {
+	case !a.Active:
+		sev = models.SeverityCritical
+		parts = append(parts, "inactive")
+	case len(a.Failed) > 0:
+		sev = models.SeverityCritical
+		parts = append(parts, "failed: "+strings.Join(a.Failed, ", "))
+	case a.Degraded():
+		sev = models.SeverityCritical
+		parts = append(parts, "degraded")
+	}
+
+	switch {
+	case a.SyncAction == "check":
+		// Scheduled scrub: informational only
+		parts = append(parts, fmt.Sprintf("check %.1f%%", a.SyncPercent))
+	case a.SyncAction != "" && a.SyncFinish == "":
+		if sev == "" {
+			sev = models.SeverityWarning
+		}
+		parts = append(parts, a.SyncAction+" pending")
+	case a.SyncAction != "":
+		if sev == "" {
+			sev = models.SeverityWarning
+		}
+		parts = append(parts, fmt.Sprintf("%s %.1f%% ETA %s", a.SyncAction, a.SyncPercent, a.SyncFinish))
+	}
+
+	value := strings.Join(parts, " ")
+	if sev == "" {
+		return nil, value
+	}
+	return &sev, value
+}
+
+// parseMdstat parses the content of /proc/mdstat
+func parseMdstat(r io.Reader) ([]mdArray, error) {
+	var arrays []mdArray
+	var current *mdArray
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if trimmed == "" || strings.HasPrefix(trimmed, "Personalities") || strings.HasPrefix(trimmed, "unused devices") {
+			continue
+		}
+
+		// Device line: "md0 : active raid1 sdb1[1] sda1[0](F)"
+		if !strings.HasPrefix(line, " ") && strings.Contains(line, " : ") {
+			name, rest, _ := strings.Cut(line, " : ")
+			arrays = append(arrays, mdArray{Name: strings.TrimSpace(name)})
+			current = &arrays[len(arrays)-1]
+			parseMdDeviceLine(current, strings.Fields(rest))
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+
+		if m := mdStatusPattern.FindStringSubmatch(trimmed); m != nil {
+			current.Devices, _ = strconv.Atoi(m[1])
+			current.ActiveDevs, _ = strconv.Atoi(m[2])
+			current.Status = m[3]
+		}
+		if m := mdSyncPattern.FindStringSubmatch(trimmed); m != nil {
+			current.SyncAction = m[1]
+			current.SyncPercent, _ = strconv.ParseFloat(m[2], 64)
+			current.SyncFinish = m[3]
+		} else if m := mdSyncPendingPattern.FindStringSubmatch(trimmed); m != nil {
+			current.SyncAction = m[1]
+		}
+	}
+
+	return arrays, scanner.Err()
+}
+
+// parseMdDeviceLine fills state, level and failed members from the fields
+// following "mdX : "
+func parseMdDeviceLine(a *mdArray, fields []string) {
+	if len(fields) == 0 {
+		return
+	}
+
+	a.Active = fields[0] == "active"
+	fields = fields[1:]
+
+	// "(read-only)" / "(auto-read-only)" may precede the level
+	for len(fields) > 0 && strings.HasPrefix(fields[0], "(") {
+		fields = fields[1:]
+	}
+
+	for _, field := range fields {
+		if strings.HasPrefix(field, "raid") || field == "linear" || field == "multipath" {
+			if a.Level == "" {
+				a.Level = field
+			}
+			continue
+		}
+		if strings.HasSuffix(field, "(F)") {
+			member, _, _ := strings.Cut(field, "[")
+			a.Failed = append(a.Failed, member)
+		}
+	}
+}
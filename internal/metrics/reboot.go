@@ -1,7 +1,18 @@
 package metrics
 
 import (
+	"bufio"
+	"errors"
+	"fmt"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
 
 	"github.com/Gu1llaum-3/tinymonitor/internal/config"
 	"github.com/Gu1llaum-3/tinymonitor/internal/models"
@@ -11,13 +22,38 @@ import (
 type RebootCollector struct {
 	name   string
 	config config.RebootConfig
+
+	// System paths and commands, replaceable in tests
+	flagFiles     []string
+	modulesDirs   []string
+	bootDir       string
+	osReleasePath string
+	procRoot      string
+	run           CommandRunner
+	lookPath      func(file string) (string, error)
+
+	// needs-restarting and the deleted libraries scan are expensive: they
+	// run in the background and their results are cached for
+	// config.Interval seconds
+	mu           sync.Mutex
+	refreshing   bool
+	lastRefresh  time.Time
+	needsRestart string
+	deletedLibs  []string
 }
 
 // NewRebootCollector creates a new reboot collector
 func NewRebootCollector(cfg config.RebootConfig) *RebootCollector {
 	return &RebootCollector{
-		name:   "reboot",
-		config: cfg,
+		name:          "reboot",
+		config:        cfg,
+		flagFiles:     []string{"/var/run/reboot-required", "/run/reboot-required"},
+		modulesDirs:   []string{"/lib/modules", "/usr/lib/modules"},
+		bootDir:       "/boot",
+		osReleasePath: "/proc/sys/kernel/osrelease",
+		procRoot:      "/proc",
+		run:           execCommand,
+		lookPath:      exec.LookPath,
 	}
 }
 
@@ -31,27 +67,336 @@ func (c *RebootCollector) Duration() int {
 	return c.config.Duration
 }
 
-// Check executes the reboot check
+// Check executes the reboot check. The results of needs-restarting and of the
+// deleted libraries scan come from the cache, refreshed in the background.
 func (c *RebootCollector) Check() []models.MetricResult {
-	rebootRequired := false
-	details := "OK"
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var reasons []string
 
 	// Debian / Ubuntu / Mint standard
 	// Check for the flag file created by apt/dpkg
-	if fileExists("/var/run/reboot-required") || fileExists("/run/reboot-required") {
-		rebootRequired = true
-		details = "System requires a reboot (updates installed)"
+	for _, flag := range c.flagFiles {
+		if fileExists(flag) {
+			reason := "updates installed"
+			if pkgs := readLinesUnique(flag + ".pkgs"); len(pkgs) > 0 {
+				reason += ": " + strings.Join(pkgs, ", ")
+			}
+			reasons = append(reasons, reason)
+			break
+		}
+	}
+
+	// Any distribution: a newer kernel is installed than the one running
+	if c.config.KernelCheck {
+		if running, newest := c.kernelVersions(); newest != "" && compareVersions(newest, running) > 0 {
+			reasons = append(reasons, fmt.Sprintf("running kernel %s, newest installed %s", running, newest))
+		}
+	}
+
+	interval := time.Duration(c.config.Interval) * time.Second
+	if (c.config.NeedsRestarting || c.config.DeletedLibs) && !c.refreshing &&
+		(c.lastRefresh.IsZero() || time.Since(c.lastRefresh) >= interval) {
+		c.refreshing = true
+		go c.refresh()
+	}
+
+	// RHEL / Rocky / Alma / Fedora (dnf-utils)
+	if c.config.NeedsRestarting && c.needsRestart != "" {
+		reasons = append(reasons, c.needsRestart)
 	}
 
+	details := "OK"
 	var level *models.Severity
-	if rebootRequired {
+	if len(reasons) > 0 {
 		sev := models.SeverityWarning
 		level = &sev
+		details = "System requires a reboot (" + strings.Join(reasons, "; ") + ")"
 	}
 
-	return []models.MetricResult{
+	results := []models.MetricResult{
 		models.NewMetricResult("REBOOT", level, details),
 	}
+
+	// Nothing to report until the first scan completes
+	if c.config.DeletedLibs && !c.lastRefresh.IsZero() {
+		var libsLevel *models.Severity
+		value := "No process uses deleted libraries"
+		if n := len(c.deletedLibs); n > 0 {
+			sev := models.SeverityWarning
+			libsLevel = &sev
+			value = fmt.Sprintf("%d process(es) use deleted libraries: %s", n, truncate(strings.Join(c.deletedLibs, ", "), 200))
+		}
+		results = append(results, models.NewMetricResult("RESTART", libsLevel, value))
+	}
+
+	return results
+}
+
+// refresh runs needs-restarting and the deleted libraries scan and updates
+// the cache
+func (c *RebootCollector) refresh() {
+	var needsRestart string
+	var deletedLibs []string
+	if c.config.NeedsRestarting {
+		needsRestart = c.checkNeedsRestarting()
+	}
+	if c.config.DeletedLibs {
+		deletedLibs = c.processesWithDeletedLibs()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.refreshing = false
+	c.lastRefresh = time.Now()
+	c.needsRestart = needsRestart
+	c.deletedLibs = deletedLibs
+}
+
+// checkNeedsRestarting runs "needs-restarting -r", which exits with 1 when a
+// reboot is required. It returns the reason, or "" if no reboot is needed or
+// the tool is not installed.
+func (c *RebootCollector) checkNeedsRestarting() string {
+	if _, err := c.lookPath("needs-restarting"); err != nil {
+		return ""
+	}
+
+	out, err := c.run("needs-restarting", "-r")
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		// "Core libraries or services have been updated since boot-up:\n  * kernel\n  * glibc"
+		var pkgs []string
+		for _, line := range splitLines(out) {
+			if pkg, ok := strings.CutPrefix(strings.TrimSpace(line), "* "); ok {
+				pkgs = append(pkgs, pkg)
+			}
+		}
+		if len(pkgs) > 0 {
+			return "core packages updated: " + strings.Join(pkgs, ", ")
+		}
+		return "needs-restarting reports a reboot is required"
+	}
+
+	return ""
+}
+
+// kernelVersions returns the running kernel release and the newest release
+// of the same flavor installed in /lib/modules (or /boot/vmlinuz-* when there
+// is no modules dir)
+func (c *RebootCollector) kernelVersions() (running, newest string) {
+	running = readTrimmed(c.osReleasePath)
+	if running == "" {
+		return "", ""
+	}
+
+	var installed []string
+	for _, dir := range c.modulesDirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			// Leftover directories of removed kernels only hold extra
+			// modules; a usable kernel always has modules.dep
+			if entry.IsDir() && fileExists(filepath.Join(dir, entry.Name(), "modules.dep")) {
+				installed = append(installed, entry.Name())
+			}
+		}
+		if len(installed) > 0 {
+			break
+		}
+	}
+
+	if len(installed) == 0 {
+		images, _ := filepath.Glob(filepath.Join(c.bootDir, "vmlinuz-*"))
+		for _, image := range images {
+			installed = append(installed, strings.TrimPrefix(filepath.Base(image), "vmlinuz-"))
+		}
+	}
+
+	flavor := kernelFlavor(running)
+	for _, version := range installed {
+		// Other flavors (linux-lts next to linux, -rt next to generic) are
+		// separate kernels, not upgrades of the running one
+		if kernelFlavor(version) != flavor {
+			continue
+		}
+		if newest == "" || compareVersions(version, newest) > 0 {
+			newest = version
+		}
+	}
+
+	return running, newest
+}
+
+// kernelFlavor identifies the kernel package a release belongs to: the
+// dash-separated fields after the version and ABI number, without their
+// digits, plus any "+" suffix. 6.1.0-18-amd64 and 6.1.0-20-amd64 share a
+// flavor; 6.1.0-20-rt-amd64, 6.6.30-1-lts and
+// 5.14.0-362.el9.x86_64+debug each have another one.
+func kernelFlavor(release string) string {
+	release, variant, _ := strings.Cut(release, "+")
+
+	fields := strings.Split(release, "-")
+	for len(fields) > 0 && fields[0] != "" && unicode.IsDigit(rune(fields[0][0])) {
+		fields = fields[1:]
+	}
+
+	var flavor []string
+	for _, field := range fields {
+		// Arch numbers its patch level: 6.8.9-arch1-2, 6.9.1-arch2-1
+		if field = strings.TrimRight(field, "0123456789"); field != "" {
+			flavor = append(flavor, field)
+		}
+	}
+
+	if variant != "" {
+		flavor = append(flavor, "+"+variant)
+	}
+	return strings.Join(flavor, "-")
+}
+
+// processesWithDeletedLibs returns the names of processes that still map a
+// shared library deleted from disk (typically replaced by an upgrade).
+// Processes that cannot be inspected are skipped.
+func (c *RebootCollector) processesWithDeletedLibs() []string {
+	entries, err := os.ReadDir(c.procRoot)
+	if err != nil {
+		return nil
+	}
+
+	names := make(map[string]bool)
+	for _, entry := range entries {
+		pid := entry.Name()
+		if _, err := strconv.Atoi(pid); err != nil {
+			continue
+		}
+
+		if mapsDeletedLib(filepath.Join(c.procRoot, pid, "maps")) {
+			name := readTrimmed(filepath.Join(c.procRoot, pid, "comm"))
+			if name == "" {
+				name = pid
+			}
+			names[name] = true
+		}
+	}
+
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// mapsDeletedLib reports whether a /proc/<pid>/maps file references a deleted
+// shared library
+func mapsDeletedLib(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasSuffix(line, "(deleted)") && strings.Contains(line, ".so") {
+			return true
+		}
+	}
+	return false
+}
+
+// compareVersions compares two version strings chunk by chunk, numeric chunks
+// numerically ("6.1.0-18-amd64" < "6.1.0-20-amd64", "5.14.0-9" < "5.14.0-10").
+// As in rpm, a numeric chunk is newer than an alphabetic one
+// ("4.18.0-513.el8" < "4.18.0-513.5.1.el8").
+func compareVersions(a, b string) int {
+	ca, cb := versionChunks(a), versionChunks(b)
+	for i := 0; i < len(ca) && i < len(cb); i++ {
+		na, errA := strconv.Atoi(ca[i])
+		nb, errB := strconv.Atoi(cb[i])
+		switch {
+		case errA == nil && errB == nil:
+			if na != nb {
+				if na < nb {
+					return -1
+				}
+				return 1
+			}
+		case errA == nil:
+			return 1
+		case errB == nil:
+			return -1
+		case ca[i] != cb[i]:
+			if ca[i] < cb[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(ca) < len(cb):
+		return -1
+	case len(ca) > len(cb):
+		return 1
+	}
+	return 0
+}
+
+// versionChunks splits a version into runs of digits and runs of letters,
+// dropping separators
+func versionChunks(v string) []string {
+	var chunks []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			chunks = append(chunks, string(current))
+			current = current[:0]
+		}
+	}
+
+	for _, r := range v {
+		switch {
+		case unicode.IsDigit(r):
+			if len(current) > 0 && !unicode.IsDigit(current[0]) {
+				flush()
+			}
+			current = append(current, r)
+		case unicode.IsLetter(r):
+			if len(current) > 0 && unicode.IsDigit(current[0]) {
+				flush()
+			}
+			current = append(current, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return chunks
+}
+
+// readLinesUnique returns the distinct non-empty lines of a file, in order
+func readLinesUnique(path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !seen[line] {
+			seen[line] = true
+			lines = append(lines, line)
+		}
+	}
+	return lines
 }
 
 func fileExists(path string) bool {
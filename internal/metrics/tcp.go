@@ -0,0 +1,288 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Gu1llaum-3/tinymonitor/internal/config"
+	"github.com/Gu1llaum-3/tinymonitor/internal/models"
+)
+
+// TCP states as encoded in the "st" column of /proc/net/tcp
+const (
+	tcpEstablished = 0x01
+	tcpTimeWait    = 0x06
+	tcpCloseWait   = 0x08
+)
+
+// tcpCounters holds the cumulative kernel counters used for rate thresholds
+type tcpCounters struct {
+	listenOverflows uint64
+	listenDrops     uint64
+	retransSegs     uint64
+}
+
+// TCPCollector summarises TCP sockets by state and watches listen queue
+// overflows and retransmissions. Socket counts come from /proc/net/tcp{,6};
+// counters from /proc/net/netstat and /proc/net/snmp are turned into rates.
+type TCPCollector struct {
+	name     string
+	config   config.TCPConfig
+	procRoot string
+	ports    map[uint64]bool
+
+	mu           sync.Mutex
+	lastCounters *tcpCounters
+	lastTime     time.Time
+}
+
+// NewTCPCollector creates a new TCP collector
+func NewTCPCollector(cfg config.TCPConfig) *TCPCollector {
+	procRoot := cfg.ProcRoot
+	if procRoot == "" {
+		procRoot = "/proc"
+	}
+
+	c := &TCPCollector{
+		name:     "tcp",
+		config:   cfg,
+		procRoot: procRoot,
+	}
+
+	if len(cfg.Ports) > 0 {
+		c.ports = make(map[uint64]bool, len(cfg.Ports))
+		for _, port := range cfg.Ports {
+			c.ports[uint64(port)] = true
+		}
+	}
+
+	if counters, err := c.readCounters(); err == nil {
+		c.lastCounters = &counters
+		c.lastTime = time.Now()
+	}
+
+	return c
+}
+
+// Name returns the collector name
+func (c *TCPCollector) Name() string {
+	return c.name
+}
+
+// Duration returns the configured duration threshold
+func (c *TCPCollector) Duration() int {
+	return c.config.Duration
+}
+
+// Check executes the TCP check. Only statistics with a configured threshold
+// are reported.
+func (c *TCPCollector) Check() []models.MetricResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var results []models.MetricResult
+
+	states, err := c.countStates()
+	if err == nil {
+		suffix := " sockets"
+		if len(c.config.Ports) > 0 {
+			suffix += fmt.Sprintf(" (ports %s)", joinInts(c.config.Ports))
+		}
+
+		addCount := func(component string, state int, threshold config.TCPThreshold) {
+			if !threshold.Active() {
+				return
+			}
+			count := float64(states[state])
+			results = append(results, models.NewMetricResult(component, tcpLevel(count, threshold),
+				fmt.Sprintf("%d%s", states[state], suffix)).WithReading(count, threshold.Warning, threshold.Critical))
+		}
+		addCount("TCP:ESTABLISHED", tcpEstablished, c.config.Established)
+		addCount("TCP:CLOSE_WAIT", tcpCloseWait, c.config.CloseWait)
+		addCount("TCP:TIME_WAIT", tcpTimeWait, c.config.TimeWait)
+	}
+
+	currentTime := time.Now()
+	counters, err := c.readCounters()
+	if err != nil {
+		return results
+	}
+
+	if c.lastCounters == nil {
+		c.lastCounters = &counters
+		c.lastTime = currentTime
+		return results
+	}
+
+	timeDelta := currentTime.Sub(c.lastTime).Seconds()
+	last := c.lastCounters
+	c.lastCounters = &counters
+	c.lastTime = currentTime
+	if timeDelta <= 0 {
+		return results
+	}
+
+	if c.config.ListenOverflows.Active() {
+		overflows := counterRate(counters.listenOverflows, last.listenOverflows, timeDelta)
+		drops := counterRate(counters.listenDrops, last.listenDrops, timeDelta)
+		results = append(results, models.NewMetricResult("TCP:LISTEN_OVERFLOWS",
+			tcpLevel(overflows, c.config.ListenOverflows),
+			fmt.Sprintf("%.2f/s (drops: %.2f/s)", overflows, drops)).
+			WithReading(overflows, c.config.ListenOverflows.Warning, c.config.ListenOverflows.Critical))
+	}
+
+	if c.config.Retransmits.Active() {
+		retrans := counterRate(counters.retransSegs, last.retransSegs, timeDelta)
+		results = append(results, models.NewMetricResult("TCP:RETRANSMITS",
+			tcpLevel(retrans, c.config.Retransmits),
+			fmt.Sprintf("%.2f segments/s", retrans)).
+			WithReading(retrans, c.config.Retransmits.Warning, c.config.Retransmits.Critical))
+	}
+
+	return results
+}
+
+// tcpLevel applies a threshold where a zero level is disabled
+func tcpLevel(value float64, t config.TCPThreshold) *models.Severity {
+	if t.Critical > 0 && value >= t.Critical {
+		sev := models.SeverityCritical
+		return &sev
+	}
+	if t.Warning > 0 && value >= t.Warning {
+		sev := models.SeverityWarning
+		return &sev
+	}
+	return nil
+}
+
+// counterRate returns the per-second increase of a counter; a counter that
+// went backwards (reset) yields 0
+func counterRate(current, last uint64, seconds float64) float64 {
+	if current < last {
+		return 0
+	}
+	return float64(current-last) / seconds
+}
+
+// countStates counts IPv4 and IPv6 sockets by state, restricted to the
+// configured local ports if any
+func (c *TCPCollector) countStates() (map[int]int, error) {
+	states := make(map[int]int)
+	found := false
+
+	for _, name := range []string{"net/tcp", "net/tcp6"} {
+		f, err := os.Open(filepath.Join(c.procRoot, name))
+		if err != nil {
+			continue
+		}
+		found = true
+
+		scanner := bufio.NewScanner(f)
+		scanner.Scan() // header
+		for scanner.Scan() {
+			// "sl local_address rem_address st ..."
+			fields := strings.Fields(scanner.Text())
+			if len(fields) < 4 {
+				continue
+			}
+
+			if c.ports != nil {
+				_, portHex, ok := strings.Cut(fields[1], ":")
+				if !ok {
+					continue
+				}
+				port, err := strconv.ParseUint(portHex, 16, 16)
+				if err != nil || !c.ports[port] {
+					continue
+				}
+			}
+
+			state, err := strconv.ParseUint(fields[3], 16, 8)
+			if err != nil {
+				continue
+			}
+			states[int(state)]++
+		}
+		f.Close()
+	}
+
+	if !found {
+		return nil, fmt.Errorf("no TCP socket table found in %s", c.procRoot)
+	}
+	return states, nil
+}
+
+// readCounters reads ListenOverflows/ListenDrops from /proc/net/netstat and
+// RetransSegs from /proc/net/snmp
+func (c *TCPCollector) readCounters() (tcpCounters, error) {
+	var counters tcpCounters
+
+	netstat, err := readProcNetStats(filepath.Join(c.procRoot, "net/netstat"))
+	if err != nil {
+		return counters, err
+	}
+	snmp, err := readProcNetStats(filepath.Join(c.procRoot, "net/snmp"))
+	if err != nil {
+		return counters, err
+	}
+
+	counters.listenOverflows = netstat["TcpExt"]["ListenOverflows"]
+	counters.listenDrops = netstat["TcpExt"]["ListenDrops"]
+	counters.retransSegs = snmp["Tcp"]["RetransSegs"]
+	return counters, nil
+}
+
+// readProcNetStats parses the "Prefix: name1 name2 ..." / "Prefix: v1 v2 ..."
+// line pairs used by /proc/net/netstat and /proc/net/snmp
+func readProcNetStats(path string) (map[string]map[string]uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	stats := make(map[string]map[string]uint64)
+	var header []string
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		if header == nil || header[0] != fields[0] {
+			header = fields
+			continue
+		}
+
+		prefix := strings.TrimSuffix(fields[0], ":")
+		values := make(map[string]uint64, len(fields)-1)
+		for i := 1; i < len(fields) && i < len(header); i++ {
+			// Some snmp values (e.g. Tcp MaxConn) are signed
+			v, err := strconv.ParseInt(fields[i], 10, 64)
+			if err != nil || v < 0 {
+				continue
+			}
+			values[header[i]] = uint64(v)
+		}
+		stats[prefix] = values
+		header = nil
+	}
+
+	return stats, scanner.Err()
+}
+
+func joinInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,261 @@
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Gu1llaum-3/tinymonitor/internal/config"
+	"github.com/Gu1llaum-3/tinymonitor/internal/models"
+)
+
+// sensorReading is a single temperature reading in °C. Max and Crit are the
+// sensor's own limits (0 when not exposed).
+type sensorReading struct {
+	Label string
+	Temp  float64
+	Max   float64
+	Crit  float64
+}
+
+// TemperatureCollector monitors hardware temperature sensors exposed by the
+// hwmon and thermal sysfs classes
+type TemperatureCollector struct {
+	name    string
+	config  config.TemperatureConfig
+	sysRoot string
+}
+
+// NewTemperatureCollector creates a new temperature collector
+func NewTemperatureCollector(cfg config.TemperatureConfig) *TemperatureCollector {
+	sysRoot := cfg.SysRoot
+	if sysRoot == "" {
+		sysRoot = "/sys"
+	}
+
+	return &TemperatureCollector{
+		name:    "temperature",
+		config:  cfg,
+		sysRoot: sysRoot,
+	}
+}
+
+// Name returns the collector name
+func (c *TemperatureCollector) Name() string {
+	return c.name
+}
+
+// Duration returns the configured duration threshold
+func (c *TemperatureCollector) Duration() int {
+	return c.config.Duration
+}
+
+// Check executes the temperature check, emitting one result per sensor
+func (c *TemperatureCollector) Check() []models.MetricResult {
+	readings := append(c.readHwmon(), c.readThermal()...)
+
+	var results []models.MetricResult
+	seen := make(map[string]int)
+
+	for _, r := range readings {
+		// Disambiguate sensors sharing a label (e.g. several "acpitz" zones)
+		seen[r.Label]++
+		if n := seen[r.Label]; n > 1 {
+			r.Label = fmt.Sprintf("%s#%d", r.Label, n)
+		}
+
+		if c.isExcluded(r.Label) {
+			continue
+		}
+
+		warning, critical := c.thresholdsFor(r)
+
+		var level *models.Severity
+		if critical > 0 && r.Temp >= critical {
+			sev := models.SeverityCritical
+			level = &sev
+		} else if warning > 0 && r.Temp >= warning {
+			sev := models.SeverityWarning
+			level = &sev
+		}
+
+		results = append(results, models.NewMetricResult("TEMP:"+r.Label, level, fmt.Sprintf("%.1f°C", r.Temp)).
+			WithReading(r.Temp, warning, critical))
+	}
+
+	return results
+}
+
+// thresholdsFor resolves the thresholds of a sensor: a matching [temperature.sensors]
+// entry first, then the global thresholds, then the sensor's own limits
+// (critical = crit, warning = max, or crit - 10 °C when max is not exposed).
+// A zero threshold disables that level.
+func (c *TemperatureCollector) thresholdsFor(r sensorReading) (warning, critical float64) {
+	warning, critical = c.config.Warning, c.config.Critical
+
+	// An exact label takes precedence over globs; among globs the first in
+	// sorted order wins so the result does not depend on map iteration.
+	patterns := make([]string, 0, len(c.config.Sensors))
+	for pattern := range c.config.Sensors {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	var override *config.SensorThreshold
+	for _, pattern := range patterns {
+		t := c.config.Sensors[pattern]
+		if pattern == r.Label {
+			override = &t
+			break
+		}
+		if override == nil {
+			if ok, _ := filepath.Match(pattern, r.Label); ok {
+				override = &t
+			}
+		}
+	}
+	if override != nil {
+		if override.Warning > 0 {
+			warning = override.Warning
+		}
+		if override.Critical > 0 {
+			critical = override.Critical
+		}
+	}
+
+	if critical == 0 {
+		critical = r.Crit
+	}
+	if warning == 0 {
+		switch {
+		case r.Max > 0 && (critical == 0 || r.Max < critical):
+			warning = r.Max
+		case critical > 10:
+			warning = critical - 10
+		}
+	}
+
+	return warning, critical
+}
+
+func (c *TemperatureCollector) isExcluded(label string) bool {
+	for _, pattern := range c.config.Exclude {
+		if ok, _ := filepath.Match(pattern, label); ok || pattern == label {
+			return true
+		}
+	}
+	return false
+}
+
+// readHwmon reads temp*_input sensors from /sys/class/hwmon. Sensors are
+// labelled "<chip name>/<temp label>" (e.g. "coretemp/Package id 0"), or
+// "<chip name>/tempN" when the chip exposes no label.
+func (c *TemperatureCollector) readHwmon() []sensorReading {
+	base := filepath.Join(c.sysRoot, "class/hwmon")
+	chips, err := os.ReadDir(base)
+	if err != nil {
+		return nil
+	}
+
+	var readings []sensorReading
+	for _, chip := range chips {
+		dir := filepath.Join(base, chip.Name())
+		chipName := readTrimmed(filepath.Join(dir, "name"))
+		if chipName == "" {
+			chipName = chip.Name()
+		}
+
+		inputs, _ := filepath.Glob(filepath.Join(dir, "temp*_input"))
+		sort.Strings(inputs)
+		for _, input := range inputs {
+			prefix := strings.TrimSuffix(filepath.Base(input), "_input")
+
+			temp, err := readMilliCelsius(input)
+			if err != nil {
+				continue
+			}
+
+			label := readTrimmed(filepath.Join(dir, prefix+"_label"))
+			if label == "" {
+				label = prefix
+			}
+
+			max, _ := readMilliCelsius(filepath.Join(dir, prefix+"_max"))
+			crit, _ := readMilliCelsius(filepath.Join(dir, prefix+"_crit"))
+
+			readings = append(readings, sensorReading{
+				Label: chipName + "/" + label,
+				Temp:  temp,
+				Max:   max,
+				Crit:  crit,
+			})
+		}
+	}
+
+	return readings
+}
+
+// readThermal reads thermal zones from /sys/class/thermal. Zones are labelled
+// by their type (e.g. "x86_pkg_temp"); "hot" and "critical" trip points are
+// used as max and crit.
+func (c *TemperatureCollector) readThermal() []sensorReading {
+	zones, _ := filepath.Glob(filepath.Join(c.sysRoot, "class/thermal/thermal_zone*"))
+	sort.Strings(zones)
+
+	var readings []sensorReading
+	for _, zone := range zones {
+		temp, err := readMilliCelsius(filepath.Join(zone, "temp"))
+		if err != nil {
+			continue
+		}
+
+		zoneType := readTrimmed(filepath.Join(zone, "type"))
+		if zoneType == "" {
+			zoneType = filepath.Base(zone)
+		}
+
+		reading := sensorReading{Label: "thermal/" + zoneType, Temp: temp}
+
+		tripTypes, _ := filepath.Glob(filepath.Join(zone, "trip_point_*_type"))
+		for _, tripType := range tripTypes {
+			tripTemp, err := readMilliCelsius(strings.TrimSuffix(tripType, "_type") + "_temp")
+			if err != nil || tripTemp <= 0 {
+				continue
+			}
+			switch readTrimmed(tripType) {
+			case "critical":
+				reading.Crit = tripTemp
+			case "hot":
+				reading.Max = tripTemp
+			}
+		}
+
+		readings = append(readings, reading)
+	}
+
+	return readings
+}
+
+// readMilliCelsius reads a sysfs temperature in millidegrees and returns °C
+func readMilliCelsius(path string) (float64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(string(data)), 64)
+	if err != nil {
+		return 0, err
+	}
+	return v / 1000, nil
+}
+
+func readTrimmed(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
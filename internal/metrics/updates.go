@@ -0,0 +1,316 @@
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Gu1llaum-3/tinymonitor/internal/config"
+	"github.com/Gu1llaum-3/tinymonitor/internal/models"
+	"github.com/Gu1llaum-3/tinymonitor/internal/state"
+)
+
+const updatesStateFile = "updates.json"
+
+// CommandRunner runs an external command and returns its standard output
+type CommandRunner func(name string, args ...string) ([]byte, error)
+
+// execCommand is the default CommandRunner. Package managers can be slow
+// (metadata refresh), so the timeout is generous.
+func execCommand(name string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	return exec.CommandContext(ctx, name, args...).Output()
+}
+
+// pendingUpdates is the result of a package manager query. Security holds
+// one key per pending security update: the package name for apt, the
+// advisory ID for dnf.
+type pendingUpdates struct {
+	Total    int
+	Security []string
+}
+
+// updatesState is persisted so the age of pending security updates survives
+// restarts
+type updatesState struct {
+	SecurityFirstSeen map[string]time.Time `json:"security_first_seen"`
+}
+
+// UpdatesCollector counts pending package updates via apt, dnf or apk. Queries
+// run in the background every Interval seconds; Check reports the cached result.
+type UpdatesCollector struct {
+	name     string
+	config   config.UpdatesConfig
+	stateDir string
+
+	// Replaceable in tests
+	run      CommandRunner
+	lookPath func(file string) (string, error)
+
+	mu          sync.Mutex
+	refreshing  bool
+	lastRefresh time.Time
+	pending     *pendingUpdates
+	state       updatesState
+}
+
+// NewUpdatesCollector creates a new pending updates collector. stateDir is
+// where the first-seen time of security updates is persisted.
+func NewUpdatesCollector(cfg config.UpdatesConfig, stateDir string) *UpdatesCollector {
+	c := &UpdatesCollector{
+		name:     "updates",
+		config:   cfg,
+		stateDir: stateDir,
+		run:      execCommand,
+		lookPath: exec.LookPath,
+		state:    updatesState{SecurityFirstSeen: make(map[string]time.Time)},
+	}
+
+	if stateDir != "" {
+		if err := state.Load(stateDir, updatesStateFile, &c.state); err != nil {
+			slog.Warn("Cannot load updates state", "error", err)
+		}
+		if c.state.SecurityFirstSeen == nil {
+			c.state.SecurityFirstSeen = make(map[string]time.Time)
+		}
+	}
+
+	return c
+}
+
+// Name returns the collector name
+func (c *UpdatesCollector) Name() string {
+	return c.name
+}
+
+// Duration returns the configured duration threshold
+func (c *UpdatesCollector) Duration() int {
+	return c.config.Duration
+}
+
+// Check reports the cached pending updates, starting a background refresh
+// when the cache is older than the configured interval
+func (c *UpdatesCollector) Check() []models.MetricResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	interval := time.Duration(c.config.Interval) * time.Second
+	if !c.refreshing && (c.lastRefresh.IsZero() || time.Since(c.lastRefresh) >= interval) {
+		c.refreshing = true
+		go c.refresh()
+	}
+
+	if c.pending == nil {
+		return nil
+	}
+
+	return []models.MetricResult{c.evaluate(time.Now())}
+}
+
+// evaluate builds the result from the cached query. Security updates pending
+// for security_max_age days or more are CRITICAL; a total count above the
+// warning threshold is a WARNING.
+func (c *UpdatesCollector) evaluate(now time.Time) models.MetricResult {
+	value := fmt.Sprintf("%d pending", c.pending.Total)
+
+	var oldest time.Duration
+	for _, key := range c.pending.Security {
+		if age := now.Sub(c.state.SecurityFirstSeen[key]); age > oldest {
+			oldest = age
+		}
+	}
+	if n := len(c.pending.Security); n > 0 {
+		value += fmt.Sprintf(" (%d security, oldest %s)", n, formatDays(oldest))
+	}
+
+	var level *models.Severity
+	maxAge := time.Duration(c.config.SecurityMaxAge) * 24 * time.Hour
+	if len(c.pending.Security) > 0 && oldest >= maxAge {
+		sev := models.SeverityCritical
+		level = &sev
+	} else if c.config.Warning > 0 && c.pending.Total >= c.config.Warning {
+		sev := models.SeverityWarning
+		level = &sev
+	}
+
+	return models.NewMetricResult("UPDATES", level, value)
+}
+
+// refresh queries the package manager and updates the cache and the
+// persisted first-seen times
+func (c *UpdatesCollector) refresh() {
+	pending, err := c.query()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.refreshing = false
+	c.lastRefresh = time.Now()
+
+	if err != nil {
+		slog.Warn("Cannot check pending updates", "error", err)
+		return
+	}
+
+	now := time.Now()
+	current := make(map[string]time.Time, len(pending.Security))
+	for _, key := range pending.Security {
+		firstSeen, ok := c.state.SecurityFirstSeen[key]
+		if !ok {
+			firstSeen = now
+		}
+		current[key] = firstSeen
+	}
+	c.state.SecurityFirstSeen = current
+	c.pending = &pending
+
+	if c.stateDir != "" {
+		if err := state.Save(c.stateDir, updatesStateFile, c.state); err != nil {
+			slog.Warn("Cannot save updates state", "error", err)
+		}
+	}
+}
+
+// query runs the configured (or detected) package manager
+func (c *UpdatesCollector) query() (pendingUpdates, error) {
+	manager := c.config.Manager
+	if manager == "" || manager == "auto" {
+		manager = c.detectManager()
+		if manager == "" {
+			return pendingUpdates{}, errors.New("no supported package manager found (apt, dnf, apk)")
+		}
+	}
+
+	switch manager {
+	case "apt":
+		return c.queryApt()
+	case "dnf":
+		return c.queryDnf()
+	case "apk":
+		return c.queryApk()
+	}
+	return pendingUpdates{}, fmt.Errorf("unsupported package manager %q", manager)
+}
+
+func (c *UpdatesCollector) detectManager() string {
+	for _, candidate := range []struct{ binary, manager string }{
+		{"apt-get", "apt"},
+		{"dnf", "dnf"},
+		{"apk", "apk"},
+	} {
+		if _, err := c.lookPath(candidate.binary); err == nil {
+			return candidate.manager
+		}
+	}
+	return ""
+}
+
+// queryApt simulates an upgrade. Package lists are not refreshed: this relies
+// on apt's periodic update (apt-daily.timer / unattended-upgrades).
+//
+//	Inst libssl3 [3.0.11-1] (3.0.13-1 Debian-Security:12/stable-security [amd64])
+func (c *UpdatesCollector) queryApt() (pendingUpdates, error) {
+	out, err := c.run("apt-get", "-s", "-o", "Debug::NoLocking=1", "upgrade")
+	if err != nil {
+		return pendingUpdates{}, fmt.Errorf("apt-get: %w", err)
+	}
+
+	var pending pendingUpdates
+	for _, line := range splitLines(out) {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "Inst" {
+			continue
+		}
+		pending.Total++
+		if strings.Contains(line, "-security") || strings.Contains(line, "Security") {
+			pending.Security = append(pending.Security, fields[1])
+		}
+	}
+	return pending, nil
+}
+
+// queryDnf counts updates with "dnf check-update" (exit code 100 when updates
+// are available) and security advisories with "dnf updateinfo list --security"
+func (c *UpdatesCollector) queryDnf() (pendingUpdates, error) {
+	out, err := c.run("dnf", "-q", "check-update")
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 100 {
+		err = nil
+	}
+	if err != nil {
+		return pendingUpdates{}, fmt.Errorf("dnf check-update: %w", err)
+	}
+
+	var pending pendingUpdates
+	for _, line := range splitLines(out) {
+		if strings.HasPrefix(line, "Obsoleting") {
+			break
+		}
+		// "openssl-libs.x86_64   1:3.0.9-2.fc38   updates"
+		fields := strings.Fields(line)
+		if len(fields) == 3 && strings.Contains(fields[0], ".") && !strings.HasPrefix(line, " ") {
+			pending.Total++
+		}
+	}
+
+	out, err = c.run("dnf", "-q", "updateinfo", "list", "--security")
+	if err != nil {
+		return pendingUpdates{}, fmt.Errorf("dnf updateinfo: %w", err)
+	}
+
+	// "FEDORA-2024-1a2b3c4d5e Important/Sec. openssl-libs-1:3.0.9-2.fc38.x86_64"
+	seen := make(map[string]bool)
+	for _, line := range splitLines(out) {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || seen[fields[0]] {
+			continue
+		}
+		seen[fields[0]] = true
+		pending.Security = append(pending.Security, fields[0])
+	}
+	sort.Strings(pending.Security)
+
+	return pending, nil
+}
+
+// queryApk lists upgradable packages. Alpine has no security classification,
+// so only the total is reported.
+//
+//	busybox-1.36.1-r5 x86_64 {busybox} (GPL-2.0-only) [upgradable from: busybox-1.36.1-r4]
+func (c *UpdatesCollector) queryApk() (pendingUpdates, error) {
+	out, err := c.run("apk", "list", "-u")
+	if err != nil {
+		return pendingUpdates{}, fmt.Errorf("apk: %w", err)
+	}
+
+	var pending pendingUpdates
+	for _, line := range splitLines(out) {
+		if strings.Contains(line, "upgradable from") {
+			pending.Total++
+		}
+	}
+	return pending, nil
+}
+
+func splitLines(data []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
+
+// formatDays formats a duration as whole days ("0d" below one day)
+func formatDays(d time.Duration) string {
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
@@ -0,0 +1,233 @@
+package metrics
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Gu1llaum-3/tinymonitor/internal/config"
+	"github.com/Gu1llaum-3/tinymonitor/internal/models"
+	"github.com/Gu1llaum-3/tinymonitor/internal/state"
+	"github.com/shirou/gopsutil/v3/host"
+)
+
+const (
+	uptimeStateFile = "uptime.json"
+
+	// bootTimeTolerance absorbs small variations of the boot time reported by
+	// the kernel (clock adjustments) that are not reboots
+	bootTimeTolerance = 60 * time.Second
+
+	// lastSeenInterval is how often the "still running" timestamp is persisted
+	lastSeenInterval = time.Minute
+)
+
+// uptimeState is persisted to detect reboots across TinyMonitor restarts
+type uptimeState struct {
+	BootTime  time.Time `json:"boot_time"`
+	LastSeen  time.Time `json:"last_seen"`
+	CleanStop bool      `json:"clean_stop"`
+}
+
+// bootEvent describes a reboot detected at startup
+type bootEvent struct {
+	previousBoot time.Time
+	lastSeen     time.Time
+	clean        bool
+}
+
+// UptimeCollector detects reboots by comparing the boot time with the one
+// recorded in the state directory, and optionally warns when the uptime
+// exceeds a maximum.
+//
+// A reboot is reported as clean when TinyMonitor was stopped properly before
+// it (e.g. by systemd during shutdown), and as unexpected otherwise (kernel
+// panic, power loss, hard reset).
+type UptimeCollector struct {
+	name     string
+	config   config.UptimeConfig
+	stateDir string
+
+	// Replaceable in tests
+	bootTime func() (time.Time, error)
+	now      func() time.Time
+
+	mu       sync.Mutex
+	loaded   bool
+	boot     time.Time
+	state    uptimeState
+	event    *bootEvent
+	lastSave time.Time
+}
+
+// NewUptimeCollector creates a new uptime collector. stateDir is where the
+// boot time is persisted; without it reboots cannot be detected.
+func NewUptimeCollector(cfg config.UptimeConfig, stateDir string) *UptimeCollector {
+	return &UptimeCollector{
+		name:     "uptime",
+		config:   cfg,
+		stateDir: stateDir,
+		bootTime: hostBootTime,
+		now:      time.Now,
+	}
+}
+
+func hostBootTime() (time.Time, error) {
+	bootTime, err := host.BootTime()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(int64(bootTime), 0), nil
+}
+
+// Name returns the collector name
+func (c *UptimeCollector) Name() string {
+	return c.name
+}
+
+// Duration returns the configured duration threshold
+func (c *UptimeCollector) Duration() int {
+	return c.config.Duration
+}
+
+// Check reports a reboot detected at startup once (BOOT), then the uptime
+// against the configured maximum (UPTIME)
+func (c *UptimeCollector) Check() []models.MetricResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+
+	if !c.loaded {
+		boot, err := c.bootTime()
+		if err != nil {
+			slog.Warn("Cannot read boot time", "error", err)
+			return nil
+		}
+		c.loaded = true
+		c.boot = boot
+		c.detectReboot(now)
+	}
+
+	var results []models.MetricResult
+
+	// A reboot is an event: alert in the first cycle, then recover
+	if c.event != nil {
+		results = append(results, c.bootResult(*c.event))
+		c.event = nil
+	} else if c.stateDir != "" {
+		results = append(results, models.NewMetricResult("BOOT", nil, "Booted at "+c.boot.Format(time.DateTime)))
+	}
+
+	if c.config.MaxDays > 0 {
+		uptime := now.Sub(c.boot)
+		var level *models.Severity
+		if uptime >= time.Duration(c.config.MaxDays)*24*time.Hour {
+			sev := models.SeverityWarning
+			level = &sev
+		}
+		results = append(results, models.NewMetricResult("UPTIME", level,
+			fmt.Sprintf("%s (max %dd)", formatUptime(uptime), c.config.MaxDays)))
+	}
+
+	if c.stateDir != "" && now.Sub(c.lastSave) >= lastSeenInterval {
+		c.state.LastSeen = now
+		c.save(now)
+	}
+
+	return results
+}
+
+// Stop records a clean stop, so the next reboot is not reported as unexpected
+func (c *UptimeCollector) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.loaded || c.stateDir == "" {
+		return
+	}
+
+	now := c.now()
+	c.state.LastSeen = now
+	c.state.CleanStop = true
+	c.save(now)
+}
+
+// detectReboot compares the current boot time with the persisted one and
+// starts recording the current boot
+func (c *UptimeCollector) detectReboot(now time.Time) {
+	if c.stateDir == "" {
+		slog.Warn("No state directory: reboots cannot be detected")
+		return
+	}
+
+	var previous uptimeState
+	if err := state.Load(c.stateDir, uptimeStateFile, &previous); err != nil {
+		slog.Warn("Cannot load uptime state", "error", err)
+	}
+
+	diff := c.boot.Sub(previous.BootTime)
+	if !previous.BootTime.IsZero() && (diff > bootTimeTolerance || diff < -bootTimeTolerance) {
+		c.event = &bootEvent{
+			previousBoot: previous.BootTime,
+			lastSeen:     previous.LastSeen,
+			clean:        previous.CleanStop,
+		}
+		c.state = uptimeState{BootTime: c.boot}
+	} else if !previous.BootTime.IsZero() {
+		// Same boot: keep the recorded boot time so small variations do
+		// not accumulate across restarts
+		c.state = uptimeState{BootTime: previous.BootTime}
+	} else {
+		c.state = uptimeState{BootTime: c.boot}
+	}
+
+	c.state.LastSeen = now
+	c.save(now)
+}
+
+// bootResult formats a detected reboot. Unexpected reboots are CRITICAL,
+// clean ones a WARNING.
+func (c *UptimeCollector) bootResult(e bootEvent) models.MetricResult {
+	value := "booted at " + c.boot.Format(time.DateTime)
+	if !e.lastSeen.IsZero() && c.boot.After(e.lastSeen) {
+		value += fmt.Sprintf(", down for ~%s", formatUptime(c.boot.Sub(e.lastSeen)))
+	}
+
+	if e.clean {
+		sev := models.SeverityWarning
+		return models.NewMetricResult("BOOT", &sev, "System rebooted (clean shutdown, "+value+")")
+	}
+
+	value = "System rebooted unexpectedly (no clean shutdown seen"
+	if !e.lastSeen.IsZero() {
+		value += ", last seen " + e.lastSeen.Format(time.DateTime)
+	}
+	value += ", booted at " + c.boot.Format(time.DateTime) + ")"
+
+	sev := models.SeverityCritical
+	return models.NewMetricResult("BOOT", &sev, value)
+}
+
+func (c *UptimeCollector) save(now time.Time) {
+	c.lastSave = now
+	if err := state.Save(c.stateDir, uptimeStateFile, c.state); err != nil {
+		slog.Warn("Cannot save uptime state", "error", err)
+	}
+}
+
+// formatUptime formats a duration as "3d 4h", "4h 12m" or "12m"
+func formatUptime(d time.Duration) string {
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // Severity represents the alert severity level
 type Severity string
@@ -9,6 +13,8 @@ const (
 	SeverityWarning  Severity = "WARNING"
 	SeverityCritical Severity = "CRITICAL"
 	SeverityRecovery Severity = "RECOVERED"
+	SeverityFlapping Severity = "FLAPPING"
+	SeverityStable   Severity = "STABLE"
 )
 
 // MetricResult represents the result of a metric check
@@ -16,6 +22,16 @@ type MetricResult struct {
 	Component string
 	Level     *Severity // nil means OK/normal
 	Value     string
+	Reading   *Reading // nil for non-numeric checks
+}
+
+// Reading is the numeric measurement behind a result and the thresholds it
+// was compared with (higher is worse, 0 or +Inf means disabled). The monitor
+// uses it to apply clear thresholds.
+type Reading struct {
+	Value    float64
+	Warning  float64
+	Critical float64
 }
 
 // Alert represents an alert to be sent
@@ -26,7 +42,8 @@ type Alert struct {
 	Title         string
 	Message       string
 	Timestamp     time.Time
-	PreviousLevel Severity // For recovery: the level before recovery
+	PreviousLevel Severity // For recovery: the level before recovery. For flapping: the worst level seen
+	Group         []Alert  // For grouped notifications: the combined alerts
 }
 
 // IsRecovery returns true if this is a recovery alert
@@ -34,11 +51,23 @@ func (a Alert) IsRecovery() bool {
 	return a.Level == SeverityRecovery
 }
 
+// IsGroup returns true if this notification combines several alerts
+func (a Alert) IsGroup() bool {
+	return len(a.Group) > 0
+}
+
+// IsFlapping returns true if this is a flapping start or end notification
+func (a Alert) IsFlapping() bool {
+	return a.Level == SeverityFlapping || a.Level == SeverityStable
+}
+
 // AlertState tracks the state of an alert for duration-based alerting
 type AlertState struct {
 	Level          Severity
-	StartTime      time.Time
+	StartTime      time.Time // Start of the current level
+	IncidentStart  time.Time // Start of the incident, kept across level changes
 	AlertTriggered bool
+	ClearedAt      time.Time // When the component went back to OK, zero while alerting
 }
 
 // NewMetricResult creates a new MetricResult
@@ -50,6 +79,12 @@ func NewMetricResult(component string, level *Severity, value string) MetricResu
 	}
 }
 
+// WithReading attaches the numeric measurement and thresholds to a result
+func (r MetricResult) WithReading(value, warning, critical float64) MetricResult {
+	r.Reading = &Reading{Value: value, Warning: warning, Critical: critical}
+	return r
+}
+
 // NewAlert creates a new Alert
 func NewAlert(component string, level Severity, value string) Alert {
 	title := "ALERT " + string(level) + " : " + component
@@ -80,3 +115,78 @@ func NewRecoveryAlert(component string, previousLevel Severity, value string) Al
 		PreviousLevel: previousLevel,
 	}
 }
+
+// NewFlappingAlert creates a notification for a component that started
+// flapping. worstLevel is the most severe level seen while flapping.
+func NewFlappingAlert(component string, worstLevel Severity, value string) Alert {
+	title := "FLAPPING : " + component
+	message := "Component " + component + " is flapping, alerts are suppressed until it is stable. Worst state: " + string(worstLevel) + ". " + value
+
+	return Alert{
+		Component:     component,
+		Level:         SeverityFlapping,
+		Value:         value,
+		Title:         title,
+		Message:       message,
+		Timestamp:     time.Now(),
+		PreviousLevel: worstLevel,
+	}
+}
+
+// NewStableAlert creates the summary sent when a flapping component is
+// stable again
+func NewStableAlert(component string, worstLevel Severity, value string) Alert {
+	title := "STABLE : " + component
+	message := "Component " + component + " stopped flapping. " + value
+
+	return Alert{
+		Component:     component,
+		Level:         SeverityStable,
+		Value:         value,
+		Title:         title,
+		Message:       message,
+		Timestamp:     time.Now(),
+		PreviousLevel: worstLevel,
+	}
+}
+
+// severityRank orders levels for grouped notifications, most severe last
+var severityRank = map[Severity]int{
+	SeverityRecovery: 1,
+	SeverityStable:   2,
+	SeverityFlapping: 3,
+	SeverityWarning:  4,
+	SeverityCritical: 5,
+}
+
+// NewGroupAlert combines several alerts into a single notification. Its level
+// is the most severe of the group and its value lists every alert, one per
+// line.
+func NewGroupAlert(alerts []Alert) Alert {
+	level := SeverityRecovery
+	components := make([]string, 0, len(alerts))
+	lines := make([]string, 0, len(alerts))
+	for _, alert := range alerts {
+		if severityRank[alert.Level] > severityRank[level] {
+			level = alert.Level
+		}
+		components = append(components, alert.Component)
+		lines = append(lines, fmt.Sprintf("- %s %s: %s", alert.Level, alert.Component, alert.Value))
+	}
+
+	title := fmt.Sprintf("ALERT %s : %d components", level, len(alerts))
+	if level == SeverityRecovery {
+		title = fmt.Sprintf("RECOVERED : %d components", len(alerts))
+	}
+	value := strings.Join(lines, "\n")
+
+	return Alert{
+		Component: strings.Join(components, ", "),
+		Level:     level,
+		Value:     value,
+		Title:     title,
+		Message:   fmt.Sprintf("%d components changed state:\n%s", len(alerts), value),
+		Timestamp: time.Now(),
+		Group:     alerts,
+	}
+}
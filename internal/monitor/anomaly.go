@@ -0,0 +1,214 @@
+package monitor
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Gu1llaum-3/tinymonitor/internal/config"
+	"github.com/Gu1llaum-3/tinymonitor/internal/models"
+	"github.com/Gu1llaum-3/tinymonitor/internal/silence"
+	"github.com/Gu1llaum-3/tinymonitor/internal/state"
+)
+
+const (
+	anomalyStateFile    = "anomaly.json"
+	anomalySaveInterval = 5 * time.Minute
+
+	defaultHalfLife         = 3600
+	defaultSeasonalHalfLife = 4 * 7 * 86400
+	defaultWarmup           = 86400
+
+	// A seasonal baseline per hour of the week
+	seasonalBuckets = 7 * 24
+)
+
+// derivedPrefixes mark the components computed from collector results
+var derivedPrefixes = []string{"TREND:", "RULE:", "ANOMALY:"}
+
+// baseline is the learned behaviour of a component (or of one hour of the
+// week of a component): exponentially weighted mean and variance
+type baseline struct {
+	Mean     float64   `json:"mean"`
+	Variance float64   `json:"variance"`
+	Count    int       `json:"count"`
+	Since    time.Time `json:"since"` // First sample
+}
+
+// update adds a sample with weight alpha
+func (b *baseline) update(value, alpha float64) {
+	if b.Count == 0 {
+		b.Mean = value
+		b.Variance = 0
+	} else {
+		diff := value - b.Mean
+		incr := alpha * diff
+		b.Mean += incr
+		b.Variance = (1 - alpha) * (b.Variance + diff*incr)
+	}
+	b.Count++
+}
+
+// loadBaselines restores the baselines learned before a restart
+func (m *Monitor) loadBaselines() {
+	if len(m.config.Anomalies) == 0 || m.config.StateDir == "" {
+		return
+	}
+	if err := state.Load(m.config.StateDir, anomalyStateFile, &m.baselines); err != nil {
+		slog.Warn("Cannot load anomaly baselines", "error", err)
+	}
+	if m.baselines == nil {
+		m.baselines = make(map[string]*baseline)
+	}
+}
+
+// saveBaselines persists the baselines, at most every anomalySaveInterval
+// unless forced (on shutdown)
+func (m *Monitor) saveBaselines(force bool) {
+	if len(m.config.Anomalies) == 0 || m.config.StateDir == "" {
+		return
+	}
+	now := m.now()
+	if !force && now.Sub(m.baselinesSaved) < anomalySaveInterval {
+		return
+	}
+	m.baselinesSaved = now
+	if err := state.Save(m.config.StateDir, anomalyStateFile, m.baselines); err != nil {
+		slog.Warn("Cannot save anomaly baselines", "error", err)
+	}
+}
+
+// anomalyFor returns the first anomaly setting matching a component, or nil
+func (m *Monitor) anomalyFor(component string) *config.AnomalyConfig {
+	for i := range m.config.Anomalies {
+		if silence.MatchGlob(m.config.Anomalies[i].Component, component) {
+			return &m.config.Anomalies[i]
+		}
+	}
+	return nil
+}
+
+// evaluateAnomalies compares the values of the components an anomaly setting
+// watches to their baseline, feeds the deviation into the alert pipeline as
+// ANOMALY:<component>, then adds the values to the baselines
+func (m *Monitor) evaluateAnomalies(latest map[string]models.MetricResult) {
+	if len(m.config.Anomalies) == 0 {
+		return
+	}
+
+	components := make([]string, 0, len(latest))
+	for component := range latest {
+		components = append(components, component)
+	}
+	slices.Sort(components)
+
+	now := m.now()
+	for _, component := range components {
+		if slices.ContainsFunc(derivedPrefixes, func(prefix string) bool {
+			return strings.HasPrefix(component, prefix)
+		}) {
+			continue
+		}
+		cfg := m.anomalyFor(component)
+		if cfg == nil {
+			continue
+		}
+		value, ok := resultField(latest[component], "value")
+		if !ok {
+			continue
+		}
+
+		key := component
+		if cfg.Seasonal {
+			// Hour of the week, 0 = Sunday 00:00
+			key += "@" + strconv.Itoa(int(now.Weekday())*24+now.Hour())
+		}
+		b := m.baselines[key]
+		if b == nil {
+			b = &baseline{Since: now}
+			m.baselines[key] = b
+		}
+
+		warmup := cfg.Warmup
+		if warmup == 0 {
+			warmup = defaultWarmup
+		}
+		if b.Count > 0 && now.Sub(b.Since) >= time.Duration(warmup)*time.Second {
+			result := anomalyResult(cfg, component, value, b)
+			latest[result.Component] = result
+
+			level := m.applyHysteresis(result)
+			change := m.processState(result.Component, level, result.Value, cfg.Duration)
+			m.notify(result.Component, result.Value, change)
+		}
+
+		b.update(value, anomalyAlpha(cfg, m.config.Refresh))
+	}
+
+	m.saveBaselines(false)
+}
+
+// anomalyAlpha returns the weight of a new sample in a baseline, so that a
+// sample weighs half after half_life seconds. A seasonal baseline is updated
+// during one hour per week: each of its samples stands for 168 refreshes.
+func anomalyAlpha(cfg *config.AnomalyConfig, refresh int) float64 {
+	interval := float64(refresh)
+	halfLife := cfg.HalfLife
+	if cfg.Seasonal {
+		interval *= seasonalBuckets
+		if halfLife == 0 {
+			halfLife = defaultSeasonalHalfLife
+		}
+	}
+	if halfLife == 0 {
+		halfLife = defaultHalfLife
+	}
+	return 1 - math.Pow(2, -interval/float64(halfLife))
+}
+
+// anomalyResult measures the deviation of a value from its baseline, in
+// standard deviations
+func anomalyResult(cfg *config.AnomalyConfig, component string, value float64, b *baseline) models.MetricResult {
+	stddev := math.Sqrt(b.Variance)
+	floor := cfg.MinStdDev
+	if floor == 0 {
+		// A flat metric would make any change infinitely anomalous
+		floor = math.Max(math.Abs(b.Mean)*0.01, 1e-9)
+	}
+	stddev = math.Max(stddev, floor)
+
+	sigmas := (value - b.Mean) / stddev
+
+	// The deviation in the watched direction
+	deviation := math.Abs(sigmas)
+	switch cfg.Direction {
+	case "up":
+		deviation = sigmas
+	case "down":
+		deviation = -sigmas
+	}
+
+	var level *models.Severity
+	if cfg.Critical > 0 && deviation >= cfg.Critical {
+		sev := models.SeverityCritical
+		level = &sev
+	} else if cfg.Warning > 0 && deviation >= cfg.Warning {
+		sev := models.SeverityWarning
+		level = &sev
+	}
+
+	warning, critical := cfg.Warning, cfg.Critical
+	if warning == 0 {
+		warning = critical
+	}
+	if critical == 0 {
+		critical = math.Inf(1)
+	}
+
+	text := fmt.Sprintf("%.1f is %+.1fσ from baseline %.1f ±%.1f", value, sigmas, b.Mean, stddev)
+	return models.NewMetricResult("ANOMALY:"+component, level, text).WithReading(deviation, warning, critical)
+}
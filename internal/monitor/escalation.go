@@ -0,0 +1,144 @@
+package monitor
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Gu1llaum-3/tinymonitor/internal/config"
+	"github.com/Gu1llaum-3/tinymonitor/internal/models"
+	"github.com/Gu1llaum-3/tinymonitor/internal/silence"
+)
+
+// escalation tracks the notifications of an incident covered by a policy
+type escalation struct {
+	policy *config.EscalationPolicy
+	start  time.Time // IncidentStart of the alert state being escalated
+	level  models.Severity
+	value  string      // Latest value, sent with repeats
+	sent   []time.Time // Last notification per step, zero if not reached yet
+}
+
+// escalationPolicy returns the first policy covering an alert, or nil
+func (m *Monitor) escalationPolicy(component string, level models.Severity) *config.EscalationPolicy {
+	for i := range m.config.Alerts.Escalation {
+		policy := &m.config.Alerts.Escalation[i]
+		if matchEscalation(policy, component, level) {
+			return policy
+		}
+	}
+	return nil
+}
+
+func matchEscalation(policy *config.EscalationPolicy, component string, level models.Severity) bool {
+	if len(policy.Levels) > 0 && !slices.ContainsFunc(policy.Levels, func(l string) bool {
+		return strings.EqualFold(l, string(level))
+	}) {
+		return false
+	}
+	if len(policy.Components) == 0 {
+		return true
+	}
+	for _, pattern := range policy.Components {
+		if silence.MatchGlob(pattern, component) {
+			return true
+		}
+	}
+	return false
+}
+
+// startEscalation replaces the cooldown for an alert covered by a policy: the
+// steps already due are notified now, the next ones by escalateAll. On a
+// level change during an incident, the escalation continues: the steps
+// already reached are notified of the new level, the later ones stay due at
+// the same time.
+func (m *Monitor) startEscalation(component string, policy *config.EscalationPolicy, level models.Severity, value string) {
+	start := m.now()
+	if state := m.alertStates[component]; state != nil {
+		start = incidentStart(state)
+	}
+
+	if e := m.escalations[component]; e != nil && e.policy == policy && e.start.Equal(start) {
+		e.level = level
+		e.value = value
+		clear(e.sent)
+		m.escalate(component, e)
+		return
+	}
+
+	e := &escalation{
+		policy: policy,
+		start:  start,
+		level:  level,
+		value:  value,
+		sent:   make([]time.Time, len(policy.Steps)),
+	}
+	m.escalations[component] = e
+	m.escalate(component, e)
+}
+
+// escalateAll notifies the escalation steps that became due. It is called
+// after each check cycle. Escalations stop when their component recovers;
+// they pause while it is flapping or waiting for the recovery duration.
+func (m *Monitor) escalateAll() {
+	for component, e := range m.escalations {
+		state := m.alertStates[component]
+		if state == nil || !state.AlertTriggered || !incidentStart(state).Equal(e.start) {
+			delete(m.escalations, component)
+			continue
+		}
+		if fs := m.flaps[component]; fs != nil && fs.flapping {
+			continue
+		}
+		if !state.ClearedAt.IsZero() {
+			continue
+		}
+		m.escalate(component, e)
+	}
+}
+
+// incidentStart returns when the incident of an alert state started
+func incidentStart(state *models.AlertState) time.Time {
+	if state.IncidentStart.IsZero() {
+		return state.StartTime
+	}
+	return state.IncidentStart
+}
+
+// escalate sends the alert to the providers of the steps due now: steps whose
+// delay elapsed and were not notified yet, or whose repeat interval elapsed.
+// It returns the providers notified.
+func (m *Monitor) escalate(component string, e *escalation) []string {
+	now := m.now()
+	elapsed := now.Sub(e.start)
+
+	var providers []string
+	for i, step := range e.policy.Steps {
+		if elapsed < time.Duration(step.After)*time.Second {
+			continue
+		}
+		if !e.sent[i].IsZero() && (step.Repeat <= 0 || now.Sub(e.sent[i]) < time.Duration(step.Repeat)*time.Second) {
+			continue
+		}
+		e.sent[i] = now
+		for _, provider := range step.Providers {
+			if !slices.Contains(providers, provider) {
+				providers = append(providers, provider)
+			}
+		}
+	}
+	if len(providers) == 0 {
+		return nil
+	}
+
+	slog.Info("ALERT",
+		"component", component,
+		"level", e.level,
+		"value", e.value,
+		"escalation", e.policy.Name,
+		"elapsed", formatElapsed(elapsed),
+		"providers", providers)
+	m.alertManager.SendAlertTo(component, e.level, e.value, providers)
+	return providers
+}
@@ -0,0 +1,159 @@
+package monitor
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Gu1llaum-3/tinymonitor/internal/models"
+)
+
+// flapState tracks the recent state changes of a component
+type flapState struct {
+	changes    []time.Time // Notifiable state changes within the window
+	lastChange time.Time
+	worst      models.Severity
+	flapping   bool
+	since      time.Time // Start of the flapping episode
+	total      int       // State changes during the episode
+	suppressed int       // Notifications suppressed during the episode
+}
+
+// detectFlapping counts the notifiable state changes of a component. Once
+// they exceed the threshold within the window, a single FLAPPING notification
+// replaces them; the following ones are suppressed until the component has
+// not changed state for the stable time, which sends a summary.
+func (m *Monitor) detectFlapping(component string, level *models.Severity, change StateChange) StateChange {
+	cfg := m.config.Flapping
+	if !cfg.Enabled {
+		return change
+	}
+
+	now := m.now()
+	fs := m.flaps[component]
+	if fs == nil {
+		if !change.ShouldAlert {
+			return change
+		}
+		fs = &flapState{}
+		m.flaps[component] = fs
+	}
+
+	if change.ShouldAlert {
+		fs.changes = append(fs.changes, now)
+		fs.lastChange = now
+		changed := change.Level
+		if change.IsRecovery {
+			changed = change.PreviousLevel
+		}
+		if fs.worst == "" || isLowerSeverity(fs.worst, changed) {
+			fs.worst = changed
+		}
+	}
+
+	// Forget changes older than the window
+	window := time.Duration(cfg.Window) * time.Second
+	expired := 0
+	for expired < len(fs.changes) && now.Sub(fs.changes[expired]) > window {
+		expired++
+	}
+	fs.changes = fs.changes[expired:]
+
+	if fs.flapping {
+		if change.ShouldAlert {
+			fs.total++
+			fs.suppressed++
+			slog.Debug("Alert suppressed (flapping)", "component", component)
+			if change.IsRecovery {
+				// No recovery is sent: forget the alert like send_recovery = false
+				m.alertManager.Resolve(component)
+			}
+			return StateChange{ShouldAlert: false}
+		}
+
+		if now.Sub(fs.lastChange) < time.Duration(cfg.StableTime)*time.Second {
+			return change
+		}
+
+		current := "OK"
+		var currentLevel models.Severity
+		if level != nil {
+			current = string(*level)
+			currentLevel = *level
+		}
+		summary := fmt.Sprintf("now %s after %d state change(s) in %s, %d notification(s) suppressed",
+			current, fs.total, formatElapsed(now.Sub(fs.since)), fs.suppressed)
+		worst := fs.worst
+		delete(m.flaps, component)
+
+		return StateChange{
+			ShouldAlert:   true,
+			IsStable:      true,
+			Level:         currentLevel,
+			PreviousLevel: worst,
+			Summary:       summary,
+		}
+	}
+
+	if len(fs.changes) > cfg.Threshold {
+		if change.IsRecovery {
+			// Replaced by the FLAPPING notification
+			m.alertManager.Resolve(component)
+		}
+		fs.flapping = true
+		fs.since = now
+		fs.total = len(fs.changes)
+		return StateChange{
+			ShouldAlert:   true,
+			IsFlapping:    true,
+			PreviousLevel: fs.worst,
+			Summary: fmt.Sprintf("%d state changes in %s, notifications suppressed until stable for %s",
+				len(fs.changes), formatElapsed(window), formatElapsed(time.Duration(cfg.StableTime)*time.Second)),
+		}
+	}
+
+	if len(fs.changes) == 0 {
+		delete(m.flaps, component)
+	}
+	return change
+}
+
+// triggerFlapping sends the FLAPPING notification (no cooldown)
+func (m *Monitor) triggerFlapping(component string, change StateChange) {
+	slog.Info("FLAPPING",
+		"component", component,
+		"worst_level", change.PreviousLevel,
+		"value", change.Summary)
+	m.alertManager.SendFlapping(component, change.PreviousLevel, change.Summary)
+}
+
+// triggerStable sends the summary of a component that stopped flapping
+func (m *Monitor) triggerStable(component string, change StateChange) {
+	slog.Info("STABLE",
+		"component", component,
+		"worst_level", change.PreviousLevel,
+		"value", change.Summary)
+	m.alertManager.SendStable(component, change.PreviousLevel, change.Summary)
+
+	if change.Level == "" {
+		// Settled OK: the alert state of the manager must not outlive it
+		m.alertManager.Resolve(component)
+	}
+
+	// Alerts resume from the current state
+	delete(m.lastAlert, component)
+}
+
+// formatElapsed formats a duration as "1h5m", "10m" or "45s"
+func formatElapsed(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	case d >= time.Minute && d%time.Minute == 0:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm%ds", int(d.Minutes()), int(d.Seconds())%60)
+	}
+	return fmt.Sprintf("%ds", int(d.Seconds()))
+}
@@ -0,0 +1,71 @@
+package monitor
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/Gu1llaum-3/tinymonitor/internal/config"
+)
+
+// heartbeatSender pings an external dead man's switch. If TinyMonitor or the
+// whole host dies, the pings stop and the external service raises the alert.
+type heartbeatSender struct {
+	config  config.OutboundHeartbeatConfig
+	client  *http.Client
+	sending atomic.Bool
+}
+
+func newHeartbeatSender(cfg config.OutboundHeartbeatConfig) *heartbeatSender {
+	return &heartbeatSender{
+		config: cfg,
+		client: &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second},
+	}
+}
+
+// sendAsync sends a heartbeat in the background so a slow endpoint never
+// delays the checks. A heartbeat still in flight is not doubled.
+func (h *heartbeatSender) sendAsync() {
+	if !h.sending.CompareAndSwap(false, true) {
+		slog.Debug("Heartbeat still in flight, skipping")
+		return
+	}
+
+	go func() {
+		defer h.sending.Store(false)
+		if err := h.send(); err != nil {
+			slog.Warn("Cannot send heartbeat", "url", h.config.URL, "error", err)
+			return
+		}
+		slog.Debug("Heartbeat sent", "url", h.config.URL)
+	}()
+}
+
+func (h *heartbeatSender) send() error {
+	method := strings.ToUpper(h.config.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	req, err := http.NewRequest(method, h.config.URL, strings.NewReader(h.config.Body))
+	if err != nil {
+		return err
+	}
+	for key, value := range h.config.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
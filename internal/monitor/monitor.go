@@ -3,20 +3,25 @@ package monitor
 import (
 	"context"
 	"log/slog"
+	"math"
 	"time"
 
 	"github.com/Gu1llaum-3/tinymonitor/internal/alerts"
 	"github.com/Gu1llaum-3/tinymonitor/internal/config"
 	"github.com/Gu1llaum-3/tinymonitor/internal/metrics"
 	"github.com/Gu1llaum-3/tinymonitor/internal/models"
+	"github.com/Gu1llaum-3/tinymonitor/internal/silence"
 )
 
 // StateChange represents the result of processing a metric state
 type StateChange struct {
 	ShouldAlert   bool
 	IsRecovery    bool
+	IsFlapping    bool // The component started flapping
+	IsStable      bool // The component stopped flapping
 	Level         models.Severity
 	PreviousLevel models.Severity
+	Summary       string // Flapping notification text
 }
 
 // Monitor is the main monitoring loop
@@ -26,6 +31,23 @@ type Monitor struct {
 	collectors   []metrics.Collector
 	lastAlert    map[string]time.Time
 	alertStates  map[string]*models.AlertState
+	flaps        map[string]*flapState
+	escalations  map[string]*escalation
+	rules        []compositeRule
+	samples      map[string]*ring // Recent values of the components trends watch
+
+	// Anomaly detection baselines, persisted in the state directory
+	baselines      map[string]*baseline
+	baselinesSaved time.Time
+
+	// Replaceable in tests
+	now func() time.Time
+
+	// Outbound heartbeat, nil when disabled. A heartbeat is only sent if a
+	// check cycle completed since the previous one.
+	heartbeat     *heartbeatSender
+	lastCycle     time.Time
+	lastHeartbeat time.Time
 }
 
 // New creates a new Monitor
@@ -36,9 +58,24 @@ func New(cfg *config.Config) *Monitor {
 		collectors:   make([]metrics.Collector, 0),
 		lastAlert:    make(map[string]time.Time),
 		alertStates:  make(map[string]*models.AlertState),
+		flaps:        make(map[string]*flapState),
+		escalations:  make(map[string]*escalation),
+		samples:      make(map[string]*ring),
+		baselines:    make(map[string]*baseline),
+		now:          time.Now,
+	}
+
+	// Maintenance windows and silences suppress delivery only: alert states
+	// keep being tracked so recoveries stay correct
+	m.alertManager.SetSilencer(silence.NewMatcher(cfg.Maintenance, cfg.StateDir))
+
+	if cfg.OutboundHeartbeat.Enabled {
+		m.heartbeat = newHeartbeatSender(cfg.OutboundHeartbeat)
 	}
 
 	m.loadCollectors()
+	m.loadRules()
+	m.loadBaselines()
 	return m
 }
 
@@ -71,15 +108,85 @@ func (m *Monitor) loadCollectors() {
 	if m.config.IO.Enabled {
 		m.collectors = append(m.collectors, metrics.NewIOCollector(m.config.IO))
 	}
+
+	if m.config.Kernel.Enabled {
+		m.collectors = append(m.collectors, metrics.NewKernelCollector(m.config.Kernel))
+	}
+
+	if m.config.RAID.Enabled {
+		m.collectors = append(m.collectors, metrics.NewRAIDCollector(m.config.RAID))
+	}
+
+	if m.config.Limits.Enabled {
+		m.collectors = append(m.collectors, metrics.NewLimitsCollector(m.config.Limits))
+	}
+
+	if m.config.TCP.Enabled {
+		m.collectors = append(m.collectors, metrics.NewTCPCollector(m.config.TCP))
+	}
+
+	if m.config.Temperature.Enabled {
+		m.collectors = append(m.collectors, metrics.NewTemperatureCollector(m.config.Temperature))
+	}
+
+	if m.config.Updates.Enabled {
+		m.collectors = append(m.collectors, metrics.NewUpdatesCollector(m.config.Updates, m.config.StateDir))
+	}
+
+	if m.config.Uptime.Enabled {
+		m.collectors = append(m.collectors, metrics.NewUptimeCollector(m.config.Uptime, m.config.StateDir))
+	}
+
+	if m.config.Cgroup.Enabled {
+		m.collectors = append(m.collectors, metrics.NewCgroupCollector(m.config.Cgroup))
+	}
+
+	if m.config.Containers.Enabled {
+		m.collectors = append(m.collectors, metrics.NewContainersCollector(m.config.Containers))
+	}
+
+	if m.config.Files.Enabled {
+		m.collectors = append(m.collectors, metrics.NewFilesCollector(m.config.Files))
+	}
+
+	if m.config.NTP.Enabled {
+		m.collectors = append(m.collectors, metrics.NewNTPCollector(m.config.NTP))
+	}
+
+	if m.config.HeartbeatListener.Enabled {
+		m.collectors = append(m.collectors, metrics.NewHeartbeatCollector(m.config.HeartbeatListener, m.config.Heartbeats, m.config.StateDir))
+	}
 }
 
 // processState manages alert state persistence
 // Returns StateChange with information about what action to take
 func (m *Monitor) processState(component string, level *models.Severity, value string, duration int) StateChange {
+	change := m.transition(component, level, duration)
+	return m.detectFlapping(component, level, change)
+}
+
+// transition applies a new level to the alert state of a component
+func (m *Monitor) transition(component string, level *models.Severity, duration int) StateChange {
+	now := m.now()
+
 	if level == nil {
 		// Return to normal: check if we need to send recovery
 		if state, exists := m.alertStates[component]; exists {
 			if state.AlertTriggered {
+				// The component must stay OK for the recovery duration
+				rule := m.config.Recovery.RuleFor(alerts.NormalizeComponentName(component))
+				if rule.Duration > 0 {
+					if state.ClearedAt.IsZero() {
+						state.ClearedAt = now
+					}
+					if now.Sub(state.ClearedAt) < time.Duration(rule.Duration)*time.Second {
+						slog.Debug("Back to normal, waiting for recovery duration",
+							"component", component,
+							"duration", rule.Duration)
+						return StateChange{ShouldAlert: false}
+					}
+				}
+
 				previousLevel := state.Level
 				delete(m.alertStates, component)
 				return StateChange{
@@ -93,8 +200,11 @@ func (m *Monitor) processState(component string, level *models.Severity, value s
 		return StateChange{ShouldAlert: false}
 	}
 
-	now := time.Now()
 	currentState := m.alertStates[component]
+	if currentState != nil {
+		// Alerting again before the recovery duration elapsed
+		currentState.ClearedAt = time.Time{}
+	}
 
 	if currentState == nil || currentState.Level != *level {
 		// Preserve AlertTriggered if transitioning to a lower severity
@@ -106,9 +216,15 @@ func (m *Monitor) processState(component string, level *models.Severity, value s
 			}
 		}
 
+		incidentStart := now
+		if currentState != nil {
+			incidentStart = currentState.IncidentStart
+		}
+
 		m.alertStates[component] = &models.AlertState{
 			Level:          *level,
 			StartTime:      now,
+			IncidentStart:  incidentStart,
 			AlertTriggered: preserveTriggered,
 		}
 
@@ -142,6 +258,42 @@ func (m *Monitor) processState(component string, level *models.Severity, value s
 	return StateChange{ShouldAlert: false}
 }
 
+// applyHysteresis returns the level of a result once clear thresholds are
+// applied: a component stays at its current alert level until its reading
+// drops below the clear threshold of that level
+func (m *Monitor) applyHysteresis(result models.MetricResult) *models.Severity {
+	state := m.alertStates[result.Component]
+	if state == nil || result.Reading == nil {
+		return result.Level
+	}
+	if result.Level != nil && !isLowerSeverity(*result.Level, state.Level) {
+		return result.Level
+	}
+
+	rule := m.config.Recovery.RuleFor(alerts.NormalizeComponentName(result.Component))
+	threshold, clear := result.Reading.Warning, rule.WarningClear
+	if state.Level == models.SeverityCritical {
+		threshold, clear = result.Reading.Critical, rule.CriticalClear
+	}
+	if clear == 0 {
+		if rule.Hysteresis == 0 || threshold <= 0 || math.IsInf(threshold, 1) {
+			return result.Level
+		}
+		clear = threshold * (1 - rule.Hysteresis/100)
+	}
+
+	if result.Reading.Value >= clear {
+		slog.Debug("Above clear threshold, keeping level",
+			"component", result.Component,
+			"level", state.Level,
+			"value", result.Reading.Value,
+			"clear", clear)
+		level := state.Level
+		return &level
+	}
+	return result.Level
+}
+
 // isLowerSeverity returns true if newLevel is less severe than oldLevel
 func isLowerSeverity(newLevel, oldLevel models.Severity) bool {
 	severityOrder := map[models.Severity]int{
@@ -151,9 +303,18 @@ func isLowerSeverity(newLevel, oldLevel models.Severity) bool {
 	return severityOrder[newLevel] < severityOrder[oldLevel]
 }
 
-// triggerAlert sends an alert with rate limiting
+// triggerAlert sends an alert through its escalation policy, or with the
+// global cooldown rate limiting when no policy covers it. Policies are opt-in
+// and may cover only some components or levels: the cooldown still rate
+// limits the alerts they leave out.
 func (m *Monitor) triggerAlert(component string, level models.Severity, value string) {
-	currentTime := time.Now()
+	if policy := m.escalationPolicy(component, level); policy != nil {
+		m.startEscalation(component, policy, level, value)
+		return
+	}
+	delete(m.escalations, component)
+
+	currentTime := m.now()
 	lastTime := m.lastAlert[component]
 
 	cooldown := m.config.Cooldown
@@ -192,8 +353,11 @@ func (m *Monitor) triggerAlert(component string, level models.Severity, value st
 
 // triggerRecovery sends a recovery notification (no cooldown)
 func (m *Monitor) triggerRecovery(component string, previousLevel models.Severity, value string) {
+	delete(m.escalations, component)
+
 	if !m.config.Alerts.SendRecovery {
 		slog.Debug("Recovery notification disabled", "component", component)
+		m.alertManager.Resolve(component)
 		return
 	}
 
@@ -207,6 +371,25 @@ func (m *Monitor) triggerRecovery(component string, previousLevel models.Severit
 	delete(m.lastAlert, component)
 }
 
+// notify sends the notification a state change calls for
+func (m *Monitor) notify(component, value string, change StateChange) {
+	if change.ShouldAlert {
+		switch {
+		case change.IsFlapping:
+			m.triggerFlapping(component, change)
+		case change.IsStable:
+			m.triggerStable(component, change)
+		case change.IsRecovery:
+			m.triggerRecovery(component, change.PreviousLevel, value)
+		default:
+			m.triggerAlert(component, change.Level, value)
+		}
+	}
+	if e := m.escalations[component]; e != nil {
+		e.value = value
+	}
+}
+
 // Run starts the monitoring loop
 func (m *Monitor) Run(ctx context.Context) {
 	slog.Info("Starting TinyMonitor...")
@@ -214,35 +397,72 @@ func (m *Monitor) Run(ctx context.Context) {
 	ticker := time.NewTicker(time.Duration(m.config.Refresh) * time.Second)
 	defer ticker.Stop()
 
+	var heartbeatTick <-chan time.Time
+	if m.heartbeat != nil {
+		heartbeatTicker := time.NewTicker(time.Duration(m.config.OutboundHeartbeat.Interval) * time.Second)
+		defer heartbeatTicker.Stop()
+		heartbeatTick = heartbeatTicker.C
+	}
+
 	// Run initial check immediately
 	m.runChecks()
+	m.sendHeartbeat()
 
 	for {
 		select {
 		case <-ctx.Done():
 			slog.Info("Stopping TinyMonitor...")
+			for _, collector := range m.collectors {
+				if stopper, ok := collector.(metrics.Stopper); ok {
+					stopper.Stop()
+				}
+			}
+			m.saveBaselines(true)
 			m.alertManager.Shutdown()
 			return
 		case <-ticker.C:
 			m.runChecks()
+		case <-heartbeatTick:
+			m.sendHeartbeat()
 		}
 	}
 }
 
+// sendHeartbeat pings the outbound heartbeat URL if a check cycle completed
+// since the previous heartbeat. A stuck cycle blocks the loop, so heartbeats
+// stop and the external watcher raises the alert.
+func (m *Monitor) sendHeartbeat() {
+	if m.heartbeat == nil {
+		return
+	}
+
+	if !m.lastCycle.After(m.lastHeartbeat) {
+		slog.Warn("Skipping heartbeat: no check cycle completed since the last one")
+		return
+	}
+
+	m.lastHeartbeat = time.Now()
+	m.heartbeat.sendAsync()
+}
+
 func (m *Monitor) runChecks() {
+	latest := make(map[string]models.MetricResult)
+
 	for _, collector := range m.collectors {
 		results := collector.Check()
 		for _, result := range results {
 			duration := collector.Duration()
-			change := m.processState(result.Component, result.Level, result.Value, duration)
-
-			if change.ShouldAlert {
-				if change.IsRecovery {
-					m.triggerRecovery(result.Component, change.PreviousLevel, result.Value)
-				} else {
-					m.triggerAlert(result.Component, change.Level, result.Value)
-				}
-			}
+			level := m.applyHysteresis(result)
+			change := m.processState(result.Component, level, result.Value, duration)
+			m.notify(result.Component, result.Value, change)
+			latest[result.Component] = result
 		}
 	}
+
+	m.evaluateTrends(latest)
+	m.evaluateAnomalies(latest)
+	m.evaluateRules(latest)
+	m.escalateAll()
+	m.alertManager.ReleaseHeld()
+	m.lastCycle = time.Now()
 }
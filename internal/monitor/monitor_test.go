@@ -1,6 +1,15 @@
 package monitor
 
 import (
+	"encoding/json"
+	"io"
+	"maps"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -366,6 +375,646 @@ func TestProcessState_LoadWindowsAreIndependent(t *testing.T) {
 	}
 }
 
+func TestApplyHysteresis(t *testing.T) {
+	cfg := &config.Config{
+		Refresh:  5,
+		Cooldown: 60,
+		Recovery: config.RecoveryConfig{
+			Hysteresis: 10,
+			Rules: map[string]config.RecoveryRule{
+				"memory": {WarningClear: 60},
+			},
+		},
+		Alerts: config.AlertsConfig{SendRecovery: true},
+	}
+	m := New(cfg)
+
+	process := func(component string, value float64) StateChange {
+		t.Helper()
+		result := models.NewMetricResult(component, nil, "").WithReading(value, 70, 90)
+		if value >= 90 {
+			result.Level = ptrSeverity(models.SeverityCritical)
+		} else if value >= 70 {
+			result.Level = ptrSeverity(models.SeverityWarning)
+		}
+		return m.processState(component, m.applyHysteresis(result), result.Value, 0)
+	}
+
+	// CPU: 10% hysteresis, WARNING clears below 63, CRITICAL below 81
+	if change := process("CPU", 92); !change.ShouldAlert || change.Level != models.SeverityCritical {
+		t.Fatalf("Expected CRITICAL alert, got %+v", change)
+	}
+	if change := process("CPU", 85); change.ShouldAlert || m.alertStates["CPU"].Level != models.SeverityCritical {
+		t.Errorf("Expected CRITICAL to hold above 81, got %+v", change)
+	}
+	if change := process("CPU", 75); !change.ShouldAlert || change.Level != models.SeverityWarning {
+		t.Errorf("Expected downgrade to WARNING below 81, got %+v", change)
+	}
+	if change := process("CPU", 65); change.ShouldAlert {
+		t.Errorf("Expected WARNING to hold above 63, got %+v", change)
+	}
+	if change := process("CPU", 60); !change.IsRecovery {
+		t.Errorf("Expected recovery below 63, got %+v", change)
+	}
+
+	// Memory: explicit clear threshold takes precedence
+	process("MEMORY", 72)
+	if change := process("MEMORY", 62); change.ShouldAlert {
+		t.Errorf("Expected WARNING to hold above 60, got %+v", change)
+	}
+	if change := process("MEMORY", 59); !change.IsRecovery {
+		t.Errorf("Expected recovery below 60, got %+v", change)
+	}
+
+	// Results without a reading are not affected
+	warning := models.SeverityWarning
+	m.processState("REBOOT", &warning, "", 0)
+	if level := m.applyHysteresis(models.NewMetricResult("REBOOT", nil, "OK")); level != nil {
+		t.Errorf("Expected nil level without reading, got %v", *level)
+	}
+}
+
+func TestProcessState_RecoveryDuration(t *testing.T) {
+	cfg := &config.Config{
+		Refresh:  5,
+		Cooldown: 60,
+		Recovery: config.RecoveryConfig{
+			Duration: 60,
+			Rules: map[string]config.RecoveryRule{
+				"filesystem": {Duration: 300},
+			},
+		},
+		Alerts: config.AlertsConfig{SendRecovery: true},
+	}
+	m := New(cfg)
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	warning := models.SeverityWarning
+	if change := m.processState("MEMORY", &warning, "75%", 0); !change.ShouldAlert {
+		t.Fatal("Expected WARNING alert")
+	}
+
+	// Back to OK, but not for long enough
+	if change := m.processState("MEMORY", nil, "60%", 0); change.ShouldAlert {
+		t.Error("Expected no recovery before the recovery duration")
+	}
+	now = now.Add(30 * time.Second)
+
+	// Flapping back above the threshold keeps the alert open silently
+	if change := m.processState("MEMORY", &warning, "71%", 0); change.ShouldAlert {
+		t.Error("Expected no new alert while the previous one is still open")
+	}
+	now = now.Add(10 * time.Second)
+	m.processState("MEMORY", nil, "60%", 0)
+	now = now.Add(59 * time.Second)
+	if change := m.processState("MEMORY", nil, "60%", 0); change.ShouldAlert {
+		t.Error("Expected the recovery duration to restart after flapping")
+	}
+	now = now.Add(time.Second)
+	if change := m.processState("MEMORY", nil, "60%", 0); !change.IsRecovery || change.PreviousLevel != models.SeverityWarning {
+		t.Errorf("Expected recovery after the recovery duration, got %+v", change)
+	}
+
+	// Per-metric override
+	m.processState("DISK:/", &warning, "86%", 0)
+	m.processState("DISK:/", nil, "80%", 0)
+	now = now.Add(time.Minute)
+	if change := m.processState("DISK:/", nil, "80%", 0); change.ShouldAlert {
+		t.Error("Expected the filesystem rule (300s) to override the global duration")
+	}
+}
+
+func TestProcessState_Flapping(t *testing.T) {
+	cfg := &config.Config{
+		Refresh:  5,
+		Cooldown: 60,
+		Flapping: config.FlappingConfig{Enabled: true, Threshold: 3, Window: 600, StableTime: 300},
+		Alerts:   config.AlertsConfig{SendRecovery: true},
+	}
+	m := New(cfg)
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	warning := models.SeverityWarning
+	critical := models.SeverityCritical
+	step := func(level *models.Severity) StateChange {
+		t.Helper()
+		now = now.Add(time.Minute)
+		return m.processState("MEMORY", level, "", 0)
+	}
+
+	// The first changes are notified normally
+	if change := step(&warning); !change.ShouldAlert || change.IsFlapping {
+		t.Fatalf("Expected a normal alert, got %+v", change)
+	}
+	if change := step(nil); !change.IsRecovery {
+		t.Fatalf("Expected a normal recovery, got %+v", change)
+	}
+	if change := step(&critical); !change.ShouldAlert || change.Level != models.SeverityCritical {
+		t.Fatalf("Expected a normal alert, got %+v", change)
+	}
+
+	// The fourth change within the window is replaced by FLAPPING
+	change := step(nil)
+	if !change.ShouldAlert || !change.IsFlapping || change.PreviousLevel != models.SeverityCritical {
+		t.Fatalf("Expected a FLAPPING notification routed as CRITICAL, got %+v", change)
+	}
+	if change.Summary != "4 state changes in 10m, notifications suppressed until stable for 5m" {
+		t.Errorf("Unexpected summary %q", change.Summary)
+	}
+
+	// Further changes are suppressed
+	if change := step(&warning); change.ShouldAlert {
+		t.Errorf("Expected the alert to be suppressed while flapping, got %+v", change)
+	}
+	for i := 0; i < 4; i++ {
+		if change := step(&warning); change.ShouldAlert {
+			t.Errorf("Expected no notification before the stable time, got %+v", change)
+		}
+	}
+
+	// Stable for 5 minutes: summary with the current state
+	change = step(&warning)
+	if !change.ShouldAlert || !change.IsStable {
+		t.Fatalf("Expected a STABLE summary, got %+v", change)
+	}
+	if change.Summary != "now WARNING after 5 state change(s) in 6m, 1 notification(s) suppressed" {
+		t.Errorf("Unexpected summary %q", change.Summary)
+	}
+
+	// Normal notifications resume
+	if change := step(nil); !change.IsRecovery || change.PreviousLevel != models.SeverityWarning {
+		t.Errorf("Expected a normal recovery after stabilising, got %+v", change)
+	}
+}
+
+// toggleSilencer silences every alert while on
+type toggleSilencer struct{ on bool }
+
+func (s *toggleSilencer) Silenced(string, models.Severity) (string, bool) {
+	return "test", s.on
+}
+
+func TestProcessState_FlappingResolvesManagerState(t *testing.T) {
+	var mu sync.Mutex
+	var delivered []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Alert struct {
+				Component string `json:"component"`
+			} `json:"alert"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		mu.Lock()
+		delivered = append(delivered, payload.Alert.Component)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		Refresh:  5,
+		Cooldown: 60,
+		Flapping: config.FlappingConfig{Enabled: true, Threshold: 3, Window: 600, StableTime: 300},
+		Alerts: config.AlertsConfig{
+			SendRecovery: true,
+			Webhook:      config.WebhookConfig{Enabled: true, URL: server.URL},
+			Inhibit:      []config.InhibitRule{{Source: "CPU", Targets: []string{"LOAD*"}}},
+		},
+	}
+	m := New(cfg)
+	now := time.Now()
+	m.now = func() time.Time { return now }
+	silencer := &toggleSilencer{on: true}
+	m.alertManager.SetSilencer(silencer)
+
+	critical := models.SeverityCritical
+	step := func(level *models.Severity) StateChange {
+		t.Helper()
+		now = now.Add(time.Minute)
+		change := m.processState("CPU", level, "", 0)
+		m.notify("CPU", "", change)
+		return change
+	}
+
+	// CPU flaps while silenced; its last CRITICAL alert is held
+	step(&critical)
+	step(nil)
+	step(&critical)
+	if change := step(nil); !change.IsFlapping {
+		t.Fatalf("Expected a FLAPPING notification, got %+v", change)
+	}
+
+	// and settles OK
+	for i := 0; i < 5; i++ {
+		if change := step(nil); change.IsStable {
+			break
+		}
+		if i == 4 {
+			t.Fatal("Expected a STABLE summary")
+		}
+	}
+
+	// Neither the held CPU alert nor an inhibition outlives the episode
+	silencer.on = false
+	m.alertManager.ReleaseHeld()
+	m.triggerAlert("LOAD5", models.SeverityWarning, "3.20")
+	m.alertManager.Shutdown()
+
+	if !slices.Equal(delivered, []string{"LOAD5"}) {
+		t.Errorf("Expected only LOAD5 to be delivered, got %v", delivered)
+	}
+}
+
+func TestEscalation(t *testing.T) {
+	cfg := &config.Config{
+		Refresh:  5,
+		Cooldown: 60,
+		Alerts: config.AlertsConfig{
+			SendRecovery: true,
+			Escalation: []config.EscalationPolicy{
+				{
+					Name:   "critical",
+					Levels: []string{"CRITICAL"},
+					Steps: []config.EscalationStep{
+						{After: 0, Providers: []string{"ntfy"}},
+						{After: 900, Providers: []string{"smtp"}},
+						{After: 1800, Providers: []string{"webhook"}, Repeat: 600},
+					},
+				},
+			},
+		},
+	}
+	m := New(cfg)
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	// Not covered by the policy: cooldown mode
+	warning := models.SeverityWarning
+	m.processState("MEMORY", &warning, "75%", 0)
+	m.triggerAlert("MEMORY", warning, "75%")
+	if m.escalations["MEMORY"] != nil {
+		t.Error("Expected no escalation for a WARNING")
+	}
+
+	critical := models.SeverityCritical
+	m.processState("CPU", &critical, "97%", 0)
+	m.triggerAlert("CPU", critical, "97%")
+	e := m.escalations["CPU"]
+	if e == nil || e.sent[0].IsZero() || !e.sent[1].IsZero() {
+		t.Fatalf("Expected the first step to be notified immediately, got %+v", e)
+	}
+
+	steps := []struct {
+		after time.Duration
+		want  []string
+	}{
+		{10 * time.Minute, nil},
+		{15 * time.Minute, []string{"smtp"}},
+		{20 * time.Minute, nil},
+		{30 * time.Minute, []string{"webhook"}},
+		{35 * time.Minute, nil},
+		{40 * time.Minute, []string{"webhook"}},
+	}
+	start := now
+	for _, step := range steps {
+		now = start.Add(step.after)
+		if got := m.escalate("CPU", e); !slices.Equal(got, step.want) {
+			t.Errorf("after %s: notified %v, want %v", step.after, got, step.want)
+		}
+	}
+
+	// Recovery stops the escalation
+	if change := m.processState("CPU", nil, "20%", 0); !change.IsRecovery {
+		t.Fatal("Expected recovery")
+	}
+	m.escalateAll()
+	if m.escalations["CPU"] != nil {
+		t.Error("Expected the escalation to stop on recovery")
+	}
+}
+
+func TestEscalationAcrossLevelChange(t *testing.T) {
+	cfg := &config.Config{
+		Refresh:  5,
+		Cooldown: 60,
+		Alerts: config.AlertsConfig{
+			SendRecovery: true,
+			Escalation: []config.EscalationPolicy{
+				{
+					Name: "all",
+					Steps: []config.EscalationStep{
+						{After: 0, Providers: []string{"ntfy"}},
+						{After: 900, Providers: []string{"smtp"}},
+						{After: 1800, Providers: []string{"webhook"}},
+					},
+				},
+			},
+		},
+	}
+	m := New(cfg)
+	start := time.Now()
+	now := start
+	m.now = func() time.Time { return now }
+
+	alert := func(level models.Severity) {
+		t.Helper()
+		if change := m.processState("CPU", &level, "", 0); !change.ShouldAlert {
+			t.Fatalf("Expected a %s alert, got %+v", level, change)
+		}
+		m.triggerAlert("CPU", level, "")
+	}
+
+	alert(models.SeverityCritical)
+	now = start.Add(16 * time.Minute)
+	m.escalateAll()
+
+	// Going down to WARNING continues the incident
+	now = start.Add(20 * time.Minute)
+	alert(models.SeverityWarning)
+	e := m.escalations["CPU"]
+	if e == nil || !e.start.Equal(start) || e.level != models.SeverityWarning {
+		t.Fatalf("Expected the escalation to continue at WARNING from the incident start, got %+v", e)
+	}
+	if e.sent[0].IsZero() || e.sent[1].IsZero() || !e.sent[2].IsZero() {
+		t.Errorf("Expected the reached steps to be notified of the new level, got %v", e.sent)
+	}
+
+	m.escalateAll()
+	if m.escalations["CPU"] != e {
+		t.Fatal("Expected the escalation to survive the level change")
+	}
+	now = start.Add(30 * time.Minute)
+	if got := m.escalate("CPU", e); !slices.Equal(got, []string{"webhook"}) {
+		t.Errorf("Expected the last step 30 minutes into the incident, notified %v", got)
+	}
+}
+
+func TestEvaluateRules(t *testing.T) {
+	cfg := &config.Config{
+		Refresh:  5,
+		Cooldown: 60,
+		Rules: []config.Rule{
+			{Name: "saturated", Expr: "CPU > 90 && LOAD5 > 4", Severity: "CRITICAL", Duration: 60, Message: "CPU {CPU}, load {LOAD5}"},
+			{Name: "chained", Expr: `"RULE:saturated".level == 2`},
+		},
+		Alerts: config.AlertsConfig{SendRecovery: true},
+	}
+	m := New(cfg)
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	results := func(cpu float64, load string) map[string]models.MetricResult {
+		return map[string]models.MetricResult{
+			"CPU":   models.NewMetricResult("CPU", nil, "busy").WithReading(cpu, 70, 95),
+			"LOAD5": models.NewMetricResult("LOAD5", nil, load),
+		}
+	}
+
+	m.evaluateRules(results(97, "5.2"))
+	state := m.alertStates["RULE:saturated"]
+	if state == nil || state.Level != models.SeverityCritical || state.AlertTriggered {
+		t.Fatalf("Expected a pending CRITICAL state for the rule, got %+v", state)
+	}
+
+	now = now.Add(time.Minute)
+	latest := results(97, "5.2")
+	m.evaluateRules(latest)
+	if !m.alertStates["RULE:saturated"].AlertTriggered {
+		t.Error("Expected the rule to alert after its duration")
+	}
+	if got := latest["RULE:saturated"].Value; got != "CPU busy, load 5.2" {
+		t.Errorf("Rule value = %q", got)
+	}
+	if state := m.alertStates["RULE:chained"]; state == nil || state.Level != models.SeverityWarning {
+		t.Errorf("Expected the chained rule to fire at WARNING, got %+v", state)
+	}
+
+	// A missing value keeps the state
+	m.evaluateRules(map[string]models.MetricResult{})
+	if m.alertStates["RULE:saturated"] == nil {
+		t.Error("Expected the rule state to be kept when values are missing")
+	}
+
+	m.evaluateRules(results(50, "1.0"))
+	if m.alertStates["RULE:saturated"] != nil {
+		t.Error("Expected the rule to recover")
+	}
+}
+
+func TestRing(t *testing.T) {
+	r := newRing(3)
+	start := time.Now()
+	if _, ok := r.latest(); ok {
+		t.Fatal("Expected an empty ring")
+	}
+	for i := 0; i < 5; i++ {
+		r.push(sample{at: start.Add(time.Duration(i) * time.Minute), value: float64(i)})
+	}
+
+	if s, _ := r.latest(); s.value != 4 {
+		t.Errorf("latest = %v, want 4", s.value)
+	}
+	if s, ok := r.before(start.Add(3*time.Minute + 30*time.Second)); !ok || s.value != 3 {
+		t.Errorf("before(3m30s) = %v, %v, want 3", s.value, ok)
+	}
+	if s, ok := r.before(start.Add(2 * time.Minute)); !ok || s.value != 2 {
+		t.Errorf("before(2m) = %v, %v, want 2", s.value, ok)
+	}
+	if _, ok := r.before(start.Add(time.Minute)); ok {
+		t.Error("Expected samples older than the buffer to be gone")
+	}
+}
+
+func TestEvaluateTrends(t *testing.T) {
+	cfg := &config.Config{
+		Refresh:  60,
+		Cooldown: 60,
+		Trends: []config.TrendConfig{
+			{Component: "MEMORY", Window: 300, Warning: 20, Critical: 30},
+			{Component: "DISK:*", Window: 600, Mode: "percent", Direction: "both", Warning: 5},
+		},
+		Alerts: config.AlertsConfig{SendRecovery: true},
+	}
+	m := New(cfg)
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	cycle := func(memory, disk float64) {
+		m.evaluateTrends(map[string]models.MetricResult{
+			"MEMORY": models.NewMetricResult("MEMORY", nil, "").WithReading(memory, 70, 90),
+			"DISK:/": models.NewMetricResult("DISK:/", nil, "").WithReading(disk, 80, 90),
+			"CPU":    models.NewMetricResult("CPU", nil, "").WithReading(50, 70, 90),
+		})
+		now = now.Add(time.Minute)
+	}
+
+	// Not enough history yet
+	for i := 0; i < 5; i++ {
+		cycle(40+float64(i)*5, 50)
+	}
+	if len(m.alertStates) != 0 {
+		t.Fatalf("Expected no trend before the window is covered, got %v", m.alertStates)
+	}
+
+	// 40 -> 65 over 5 minutes: WARNING
+	cycle(65, 50)
+	if state := m.alertStates["TREND:MEMORY"]; state == nil || state.Level != models.SeverityWarning {
+		t.Errorf("Expected a WARNING memory trend, got %+v", state)
+	}
+	if m.alertStates["TREND:CPU"] != nil {
+		t.Error("Expected no trend for a component without trend")
+	}
+
+	// 50 -> 47 over 10 minutes is -6%, alerting in both directions
+	for i := 0; i < 4; i++ {
+		cycle(65, 50)
+	}
+	cycle(65, 47)
+	if state := m.alertStates["TREND:DISK:/"]; state == nil || state.Level != models.SeverityWarning {
+		t.Errorf("Expected a WARNING filesystem trend, got %+v", state)
+	}
+	if m.alertStates["TREND:MEMORY"] != nil {
+		t.Error("Expected the memory trend to recover once stable")
+	}
+}
+
+func TestEvaluateAnomalies(t *testing.T) {
+	cfg := &config.Config{
+		Refresh:  60,
+		Cooldown: 60,
+		StateDir: t.TempDir(),
+		Anomalies: []config.AnomalyConfig{
+			{Component: "CPU", Warning: 3, Critical: 5, Warmup: 3600},
+		},
+		Alerts: config.AlertsConfig{SendRecovery: true},
+	}
+	m := New(cfg)
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	cycle := func(cpu float64) {
+		m.evaluateAnomalies(map[string]models.MetricResult{
+			"CPU": models.NewMetricResult("CPU", nil, "").WithReading(cpu, 70, 90),
+		})
+		now = now.Add(time.Minute)
+	}
+
+	// Learn 30% +/- 2 for two hours; a spike during the warm-up is ignored
+	for i := 0; i < 120; i++ {
+		value := 28.0
+		if i%2 == 0 {
+			value = 32
+		}
+		if i == 10 {
+			value = 95
+		}
+		cycle(value)
+		if i < 60 && m.alertStates["ANOMALY:CPU"] != nil {
+			t.Fatalf("Expected no anomaly during the warm-up (sample %d)", i)
+		}
+	}
+	if state := m.alertStates["ANOMALY:CPU"]; state != nil {
+		t.Fatalf("Expected no anomaly for usual values, got %+v", state)
+	}
+
+	cycle(45)
+	if state := m.alertStates["ANOMALY:CPU"]; state == nil || state.Level != models.SeverityWarning {
+		t.Errorf("Expected a WARNING anomaly, got %+v", state)
+	}
+	cycle(60)
+	if state := m.alertStates["ANOMALY:CPU"]; state == nil || state.Level != models.SeverityCritical {
+		t.Errorf("Expected a CRITICAL anomaly, got %+v", state)
+	}
+
+	// The baseline survives a restart
+	m.saveBaselines(true)
+	restarted := New(cfg)
+	b := restarted.baselines["CPU"]
+	if b == nil || b.Count != 122 || b.Mean != m.baselines["CPU"].Mean {
+		t.Errorf("Expected the baseline to be restored, got %+v", b)
+	}
+}
+
+func TestEvaluateAnomaliesSeasonal(t *testing.T) {
+	cfg := &config.Config{
+		Refresh:  60,
+		Cooldown: 60,
+		Anomalies: []config.AnomalyConfig{
+			{Component: "*", Seasonal: true, HalfLife: 7 * 86400, Warning: 3},
+		},
+	}
+	m := New(cfg)
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.Local) // Monday 10:00
+	m.now = func() time.Time { return now }
+
+	// A week of learning weighs half: one hour of values at this hour
+	key := "CPU@" + strconv.Itoa(1*24+10)
+	m.baselines[key] = &baseline{Mean: 0, Count: 1, Since: now}
+	for i := 0; i < 60; i++ {
+		m.evaluateAnomalies(map[string]models.MetricResult{
+			"CPU":       models.NewMetricResult("CPU", nil, "").WithReading(100, 70, 90),
+			"TREND:CPU": models.NewMetricResult("TREND:CPU", nil, "").WithReading(5, 10, 20),
+			"RULE:busy": models.NewMetricResult("RULE:busy", nil, "").WithReading(1, 1, 1),
+		})
+		now = now.Add(time.Minute)
+	}
+
+	if mean := m.baselines[key].Mean; math.Abs(mean-50) > 0.01 {
+		t.Errorf("Expected the previous weeks to weigh half after a week, got mean %.2f", mean)
+	}
+	if len(m.baselines) != 1 {
+		t.Errorf("Expected no baseline for derived components, got %v", slices.Collect(maps.Keys(m.baselines)))
+	}
+}
+
+func TestOutboundHeartbeat_OnlyAfterCompletedCycle(t *testing.T) {
+	requests := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- r.Method + " " + r.Header.Get("X-Token") + " " + string(body)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		Refresh:  5,
+		Cooldown: 60,
+		OutboundHeartbeat: config.OutboundHeartbeatConfig{
+			Enabled:  true,
+			URL:      server.URL,
+			Interval: 60,
+			Method:   "post",
+			Body:     "alive",
+			Headers:  map[string]string{"X-Token": "secret"},
+			Timeout:  5,
+		},
+	}
+	m := New(cfg)
+
+	// No cycle completed yet
+	m.sendHeartbeat()
+
+	m.runChecks()
+	m.sendHeartbeat()
+
+	select {
+	case got := <-requests:
+		if got != "POST secret alive" {
+			t.Errorf("Unexpected heartbeat request: %q", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Heartbeat not sent after a completed cycle")
+	}
+
+	// No new cycle since the last heartbeat
+	m.sendHeartbeat()
+	select {
+	case got := <-requests:
+		t.Errorf("Unexpected heartbeat without a new cycle: %q", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
 // ptrSeverity is a helper to create a pointer to a Severity value
 func ptrSeverity(s models.Severity) *models.Severity {
 	return &s
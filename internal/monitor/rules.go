@@ -0,0 +1,136 @@
+package monitor
+
+import (
+	"errors"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Gu1llaum-3/tinymonitor/internal/config"
+	"github.com/Gu1llaum-3/tinymonitor/internal/expr"
+	"github.com/Gu1llaum-3/tinymonitor/internal/models"
+)
+
+// compositeRule is a [[rule]] with its parsed expression
+type compositeRule struct {
+	config.Rule
+	expr  *expr.Expr
+	level models.Severity
+}
+
+// loadRules parses the composite rules. Invalid ones are logged and ignored
+// (the configuration validation reports them).
+func (m *Monitor) loadRules() {
+	for _, rule := range m.config.Rules {
+		parsed, err := expr.Parse(rule.Expr)
+		if err != nil {
+			slog.Warn("Ignoring invalid rule", "name", rule.Name, "error", err)
+			continue
+		}
+		level := models.SeverityWarning
+		if strings.EqualFold(rule.Severity, string(models.SeverityCritical)) {
+			level = models.SeverityCritical
+		}
+		m.rules = append(m.rules, compositeRule{Rule: rule, expr: parsed, level: level})
+	}
+}
+
+// evaluateRules evaluates the composite rules over the results of the cycle
+// and feeds them into the alert pipeline as RULE:<name> components. Rules
+// can use the results of the rules before them. A rule referencing a value
+// not reported in this cycle keeps its state.
+func (m *Monitor) evaluateRules(latest map[string]models.MetricResult) {
+	lookup := func(component, field string) (float64, bool) {
+		result, ok := latest[component]
+		if !ok {
+			return 0, false
+		}
+		return resultField(result, field)
+	}
+
+	for _, rule := range m.rules {
+		component := "RULE:" + rule.Name
+		firing, err := rule.expr.Eval(lookup)
+		if err != nil {
+			var missing *expr.MissingError
+			if errors.As(err, &missing) {
+				slog.Debug("Rule not evaluated", "rule", rule.Name, "error", err)
+			} else {
+				slog.Warn("Rule evaluation failed", "rule", rule.Name, "error", err)
+			}
+			continue
+		}
+
+		result := models.NewMetricResult(component, nil, ruleValue(rule, latest, false))
+		if firing {
+			level := rule.level
+			result = models.NewMetricResult(component, &level, ruleValue(rule, latest, true))
+		}
+		latest[component] = result
+
+		change := m.processState(component, result.Level, result.Value, rule.Duration)
+		m.notify(component, result.Value, change)
+	}
+}
+
+// ruleValue returns the value of a rule: its message with the placeholders
+// replaced, or the expression
+func ruleValue(rule compositeRule, latest map[string]models.MetricResult, firing bool) string {
+	if rule.Message == "" {
+		if firing {
+			return rule.Expr
+		}
+		return "not (" + rule.Expr + ")"
+	}
+	return placeholderPattern.ReplaceAllStringFunc(rule.Message, func(placeholder string) string {
+		if result, ok := latest[placeholder[1:len(placeholder)-1]]; ok {
+			return result.Value
+		}
+		return placeholder
+	})
+}
+
+var placeholderPattern = regexp.MustCompile(`\{[^{}]+\}`)
+
+// resultField returns a numeric field of a result. The value comes from its
+// reading, or from the number its value starts with ("95.2%").
+func resultField(result models.MetricResult, field string) (float64, bool) {
+	switch field {
+	case "level":
+		switch {
+		case result.Level == nil:
+			return 0, true
+		case *result.Level == models.SeverityCritical:
+			return 2, true
+		default:
+			return 1, true
+		}
+	case "warning":
+		if result.Reading == nil {
+			return 0, false
+		}
+		return result.Reading.Warning, true
+	case "critical":
+		if result.Reading == nil {
+			return 0, false
+		}
+		return result.Reading.Critical, true
+	}
+
+	if result.Reading != nil {
+		return result.Reading.Value, true
+	}
+	return leadingNumber(result.Value)
+}
+
+var leadingNumberPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?`)
+
+func leadingNumber(s string) (float64, bool) {
+	match := leadingNumberPattern.FindString(strings.TrimSpace(s))
+	if match == "" {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(match, 64)
+	return v, err == nil
+}
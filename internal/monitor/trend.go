@@ -0,0 +1,165 @@
+package monitor
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/Gu1llaum-3/tinymonitor/internal/config"
+	"github.com/Gu1llaum-3/tinymonitor/internal/models"
+	"github.com/Gu1llaum-3/tinymonitor/internal/silence"
+)
+
+// sample is a value of a component at a check cycle
+type sample struct {
+	at    time.Time
+	value float64
+}
+
+// ring keeps the last samples of a component, oldest overwritten first
+type ring struct {
+	samples []sample
+	next    int
+	full    bool
+}
+
+func newRing(size int) *ring {
+	return &ring{samples: make([]sample, size)}
+}
+
+func (r *ring) push(s sample) {
+	r.samples[r.next] = s
+	r.next = (r.next + 1) % len(r.samples)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// latest returns the newest sample
+func (r *ring) latest() (sample, bool) {
+	if !r.full && r.next == 0 {
+		return sample{}, false
+	}
+	return r.samples[(r.next-1+len(r.samples))%len(r.samples)], true
+}
+
+// before returns the newest sample taken at or before t, false if the
+// buffer does not go back that far
+func (r *ring) before(t time.Time) (sample, bool) {
+	n := r.next
+	if r.full {
+		n = len(r.samples)
+	}
+	for i := 1; i <= n; i++ {
+		s := r.samples[(r.next-i+len(r.samples))%len(r.samples)]
+		if !s.at.After(t) {
+			return s, true
+		}
+	}
+	return sample{}, false
+}
+
+// trendFor returns the first trend matching a component, or nil
+func (m *Monitor) trendFor(component string) *config.TrendConfig {
+	for i := range m.config.Trends {
+		if silence.MatchGlob(m.config.Trends[i].Component, component) {
+			return &m.config.Trends[i]
+		}
+	}
+	return nil
+}
+
+// evaluateTrends records the values of the components a trend watches and
+// feeds their change over the trend window into the alert pipeline as
+// TREND:<component>. Nothing is raised until the samples cover the window.
+func (m *Monitor) evaluateTrends(latest map[string]models.MetricResult) {
+	if len(m.config.Trends) == 0 {
+		return
+	}
+
+	components := make([]string, 0, len(latest))
+	for component := range latest {
+		components = append(components, component)
+	}
+	slices.Sort(components)
+
+	now := m.now()
+	for _, component := range components {
+		trend := m.trendFor(component)
+		if trend == nil {
+			continue
+		}
+		value, ok := resultField(latest[component], "value")
+		if !ok {
+			continue
+		}
+
+		samples := m.samples[component]
+		if samples == nil {
+			// One sample per cycle over the window, plus the one before it
+			samples = newRing(trend.Window/max(m.config.Refresh, 1) + 2)
+			m.samples[component] = samples
+		}
+		samples.push(sample{at: now, value: value})
+
+		result, ok := trendResult(trend, component, samples, now)
+		if !ok {
+			continue
+		}
+		latest[result.Component] = result
+
+		level := m.applyHysteresis(result)
+		change := m.processState(result.Component, level, result.Value, trend.Duration)
+		m.notify(result.Component, result.Value, change)
+	}
+}
+
+// trendResult computes the change of a component over the trend window
+func trendResult(trend *config.TrendConfig, component string, samples *ring, now time.Time) (models.MetricResult, bool) {
+	window := time.Duration(trend.Window) * time.Second
+	current, _ := samples.latest()
+	old, ok := samples.before(now.Add(-window))
+	if !ok {
+		return models.MetricResult{}, false
+	}
+
+	delta := current.value - old.value
+	unit := ""
+	if trend.Mode == "percent" {
+		if old.value == 0 {
+			return models.MetricResult{}, false
+		}
+		delta = delta / math.Abs(old.value) * 100
+		unit = "%"
+	}
+
+	// The change in the watched direction
+	change := delta
+	switch trend.Direction {
+	case "down":
+		change = -delta
+	case "both":
+		change = math.Abs(delta)
+	}
+
+	var level *models.Severity
+	if trend.Critical > 0 && change >= trend.Critical {
+		sev := models.SeverityCritical
+		level = &sev
+	} else if trend.Warning > 0 && change >= trend.Warning {
+		sev := models.SeverityWarning
+		level = &sev
+	}
+
+	warning, critical := trend.Warning, trend.Critical
+	if warning == 0 {
+		warning = critical
+	}
+	if critical == 0 {
+		critical = math.Inf(1)
+	}
+
+	value := fmt.Sprintf("%+.1f%s in %s (%.1f -> %.1f)", delta, unit, formatElapsed(current.at.Sub(old.at)), old.value, current.value)
+	return models.NewMetricResult("TREND:"+component, level, value).WithReading(change, warning, critical), true
+}
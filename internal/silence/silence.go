@@ -0,0 +1,297 @@
+// Package silence decides whether alerts must not be delivered: recurring
+// maintenance windows from the configuration and ad-hoc silences created
+// with "tinymonitor silence add", persisted in the state directory.
+package silence
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Gu1llaum-3/tinymonitor/internal/config"
+	"github.com/Gu1llaum-3/tinymonitor/internal/models"
+	"github.com/Gu1llaum-3/tinymonitor/internal/state"
+)
+
+const stateFile = "silences.json"
+
+// Silence is an ad-hoc silence
+type Silence struct {
+	ID        string    `json:"id"`
+	Component string    `json:"component"`
+	Levels    []string  `json:"levels,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+}
+
+// Active reports whether the silence is in effect at t
+func (s Silence) Active(t time.Time) bool {
+	return !t.Before(s.StartsAt) && t.Before(s.EndsAt)
+}
+
+// Matches reports whether the silence applies to an alert at t
+func (s Silence) Matches(component string, level models.Severity, t time.Time) bool {
+	return s.Active(t) && MatchGlob(s.Component, component) && matchLevel(s.Levels, level)
+}
+
+// Store manages the ad-hoc silences persisted in the state directory. The
+// file is read on every call, so silences added from the command line apply
+// to the running service immediately.
+type Store struct {
+	dir string
+	now func() time.Time
+}
+
+// NewStore creates a store in the state directory
+func NewStore(dir string) *Store {
+	return &Store{dir: dir, now: time.Now}
+}
+
+// List returns the silences that have not expired, by end time
+func (s *Store) List() ([]Silence, error) {
+	silences, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	var current []Silence
+	for _, silence := range silences {
+		if now.Before(silence.EndsAt) {
+			current = append(current, silence)
+		}
+	}
+	sort.Slice(current, func(i, j int) bool { return current[i].EndsAt.Before(current[j].EndsAt) })
+	return current, nil
+}
+
+// Add stores a new silence, assigning its ID. Expired silences are dropped.
+func (s *Store) Add(silence Silence) (Silence, error) {
+	if _, err := compileGlob(silence.Component); err != nil {
+		return Silence{}, err
+	}
+	if !silence.EndsAt.After(silence.StartsAt) {
+		return Silence{}, fmt.Errorf("silence must end after it starts")
+	}
+
+	current, err := s.List()
+	if err != nil {
+		return Silence{}, err
+	}
+
+	id := make([]byte, 4)
+	if _, err := rand.Read(id); err != nil {
+		return Silence{}, err
+	}
+	silence.ID = hex.EncodeToString(id)
+
+	if err := state.Save(s.dir, stateFile, append(current, silence)); err != nil {
+		return Silence{}, err
+	}
+	return silence, nil
+}
+
+// Expire ends a silence now
+func (s *Store) Expire(id string) error {
+	current, err := s.List()
+	if err != nil {
+		return err
+	}
+
+	found := false
+	var kept []Silence
+	for _, silence := range current {
+		if silence.ID == id {
+			found = true
+			continue
+		}
+		kept = append(kept, silence)
+	}
+	if !found {
+		return fmt.Errorf("no active silence with ID %q", id)
+	}
+
+	return state.Save(s.dir, stateFile, kept)
+}
+
+func (s *Store) load() ([]Silence, error) {
+	var silences []Silence
+	if err := state.Load(s.dir, stateFile, &silences); err != nil {
+		return nil, err
+	}
+	return silences, nil
+}
+
+// Window is a recurring maintenance window
+type Window struct {
+	name       string
+	days       map[time.Weekday]bool
+	start, end int // Minutes since midnight
+	location   *time.Location
+	components []*regexp.Regexp
+	levels     []string
+}
+
+// NewWindow parses a maintenance window from the configuration
+func NewWindow(cfg config.MaintenanceWindow) (*Window, error) {
+	w := &Window{name: cfg.Name, location: time.Local, levels: cfg.Levels}
+
+	var err error
+	if w.start, err = config.ParseClock(cfg.Start); err != nil {
+		return nil, err
+	}
+	if w.end, err = config.ParseClock(cfg.End); err != nil {
+		return nil, err
+	}
+	if cfg.TimeZone != "" {
+		if w.location, err = time.LoadLocation(cfg.TimeZone); err != nil {
+			return nil, err
+		}
+	}
+	if len(cfg.Days) > 0 {
+		w.days = make(map[time.Weekday]bool)
+		for _, day := range cfg.Days {
+			weekday, ok := config.Weekdays[strings.ToLower(day)]
+			if !ok {
+				return nil, fmt.Errorf("unknown day %q", day)
+			}
+			w.days[weekday] = true
+		}
+	}
+	for _, pattern := range cfg.Components {
+		re, err := compileGlob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		w.components = append(w.components, re)
+	}
+
+	return w, nil
+}
+
+// Active reports whether t falls in the window. A window wrapping past
+// midnight belongs to the day it starts on.
+func (w *Window) Active(t time.Time) bool {
+	t = t.In(w.location)
+	minute := t.Hour()*60 + t.Minute()
+
+	if w.start < w.end {
+		return minute >= w.start && minute < w.end && w.onDay(t.Weekday())
+	}
+	if minute >= w.start {
+		return w.onDay(t.Weekday())
+	}
+	if minute < w.end {
+		return w.onDay((t.Weekday() + 6) % 7)
+	}
+	return false
+}
+
+// Matches reports whether the window applies to an alert at t
+func (w *Window) Matches(component string, level models.Severity, t time.Time) bool {
+	if !w.Active(t) || !matchLevel(w.levels, level) {
+		return false
+	}
+	if len(w.components) == 0 {
+		return true
+	}
+	for _, re := range w.components {
+		if re.MatchString(component) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Window) onDay(day time.Weekday) bool {
+	return w.days == nil || w.days[day]
+}
+
+// Matcher combines the maintenance windows and the ad-hoc silences
+type Matcher struct {
+	windows []*Window
+	store   *Store
+	now     func() time.Time
+}
+
+// NewMatcher creates a matcher. Invalid windows are logged and ignored
+// (the configuration validation reports them).
+func NewMatcher(windows []config.MaintenanceWindow, stateDir string) *Matcher {
+	m := &Matcher{store: NewStore(stateDir), now: time.Now}
+	for _, cfg := range windows {
+		w, err := NewWindow(cfg)
+		if err != nil {
+			slog.Warn("Ignoring invalid maintenance window", "name", cfg.Name, "error", err)
+			continue
+		}
+		m.windows = append(m.windows, w)
+	}
+	return m
+}
+
+// Silenced reports whether an alert must not be delivered now, and why
+func (m *Matcher) Silenced(component string, level models.Severity) (string, bool) {
+	now := m.now()
+
+	for _, w := range m.windows {
+		if w.Matches(component, level, now) {
+			return "maintenance window " + w.name, true
+		}
+	}
+
+	if m.store.dir == "" {
+		return "", false
+	}
+	silences, err := m.store.load()
+	if err != nil {
+		slog.Warn("Cannot load silences", "error", err)
+		return "", false
+	}
+	for _, silence := range silences {
+		if silence.Matches(component, level, now) {
+			reason := "silence " + silence.ID
+			if silence.Reason != "" {
+				reason += " (" + silence.Reason + ")"
+			}
+			return reason, true
+		}
+	}
+
+	return "", false
+}
+
+func matchLevel(levels []string, level models.Severity) bool {
+	if len(levels) == 0 {
+		return true
+	}
+	for _, l := range levels {
+		if strings.EqualFold(l, string(level)) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchGlob matches a component against a glob where "*" matches any
+// sequence of characters, "/" included ("DISK:*" matches "DISK:/var/lib")
+func MatchGlob(pattern, component string) bool {
+	re, err := compileGlob(pattern)
+	return err == nil && re.MatchString(component)
+}
+
+func compileGlob(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("empty component pattern")
+	}
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+	return regexp.Compile("^" + expr + "$")
+}
@@ -0,0 +1,113 @@
+package silence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Gu1llaum-3/tinymonitor/internal/config"
+	"github.com/Gu1llaum-3/tinymonitor/internal/models"
+)
+
+func TestWindowActive(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	// Saturday and Sunday nights, 23:00 to 02:00 Paris time
+	w, err := NewWindow(config.MaintenanceWindow{
+		Name:     "weekend",
+		Days:     []string{"sat", "sun"},
+		Start:    "23:00",
+		End:      "02:00",
+		TimeZone: "Europe/Paris",
+	})
+	if err != nil {
+		t.Fatalf("NewWindow failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		time time.Time
+		want bool
+	}{
+		{"saturday before start", time.Date(2024, 6, 1, 22, 59, 0, 0, paris), false},
+		{"saturday after start", time.Date(2024, 6, 1, 23, 0, 0, 0, paris), true},
+		{"sunday early morning, started saturday", time.Date(2024, 6, 2, 1, 30, 0, 0, paris), true},
+		{"sunday at end", time.Date(2024, 6, 2, 2, 0, 0, 0, paris), false},
+		{"monday early morning, started sunday", time.Date(2024, 6, 3, 1, 0, 0, 0, paris), true},
+		{"tuesday early morning", time.Date(2024, 6, 4, 1, 0, 0, 0, paris), false},
+		{"saturday 21:30 UTC is 23:30 Paris", time.Date(2024, 6, 1, 21, 30, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := w.Active(tt.time); got != tt.want {
+				t.Errorf("Active(%s) = %v, want %v", tt.time, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWindowMatches(t *testing.T) {
+	w, err := NewWindow(config.MaintenanceWindow{
+		Start:      "00:00",
+		End:        "23:59",
+		Components: []string{"DISK:*", "I/O"},
+		Levels:     []string{"WARNING"},
+	})
+	if err != nil {
+		t.Fatalf("NewWindow failed: %v", err)
+	}
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.Local)
+
+	if !w.Matches("DISK:/var/lib/docker", models.SeverityWarning, now) {
+		t.Error("Expected DISK:* to match a nested mountpoint")
+	}
+	if w.Matches("DISK:/", models.SeverityCritical, now) {
+		t.Error("Expected CRITICAL not to be silenced")
+	}
+	if w.Matches("CPU", models.SeverityWarning, now) {
+		t.Error("Expected CPU not to be silenced")
+	}
+}
+
+func TestStore(t *testing.T) {
+	store := NewStore(t.TempDir())
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	disk, err := store.Add(Silence{Component: "DISK:*", Reason: "migration", StartsAt: now, EndsAt: now.Add(2 * time.Hour)})
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if _, err := store.Add(Silence{Component: "CPU", StartsAt: now, EndsAt: now.Add(time.Hour)}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if _, err := store.Add(Silence{Component: "CPU", StartsAt: now, EndsAt: now}); err == nil {
+		t.Error("Expected an error for an empty silence")
+	}
+
+	matcher := NewMatcher(nil, store.dir)
+	matcher.now = store.now
+	if reason, ok := matcher.Silenced("DISK:/srv", models.SeverityCritical); !ok || reason != "silence "+disk.ID+" (migration)" {
+		t.Errorf("Expected DISK:/srv to be silenced, got %q %v", reason, ok)
+	}
+
+	// Expired silences are not listed
+	now = now.Add(90 * time.Minute)
+	silences, err := store.List()
+	if err != nil || len(silences) != 1 || silences[0].ID != disk.ID {
+		t.Fatalf("Expected only the disk silence, got %+v %v", silences, err)
+	}
+
+	if err := store.Expire(disk.ID); err != nil {
+		t.Fatalf("Expire failed: %v", err)
+	}
+	if _, ok := matcher.Silenced("DISK:/srv", models.SeverityCritical); ok {
+		t.Error("Expected the expired silence not to apply")
+	}
+	if err := store.Expire(disk.ID); err == nil {
+		t.Error("Expected an error when expiring an unknown silence")
+	}
+}
@@ -0,0 +1,55 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// DefaultDir is the default directory for persisted state. The systemd unit
+// installed by "tinymonitor service install" makes it writable via
+// StateDirectory=.
+const DefaultDir = "/var/lib/tinymonitor"
+
+// Load reads the JSON state file name in dir into v. A missing file is not an
+// error: v is left untouched.
+func Load(dir, name string, v any) error {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Save writes v as JSON to the state file name in dir. The file is replaced
+// atomically so a crash never leaves a truncated state behind.
+func Save(dir, name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(dir, name))
+}
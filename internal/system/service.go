@@ -48,6 +48,7 @@ ProtectSystem=strict
 ProtectHome=read-only
 PrivateTmp=true
 ReadOnlyPaths=/
+StateDirectory=tinymonitor
 
 [Install]
 WantedBy=multi-user.target